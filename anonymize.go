@@ -0,0 +1,176 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ErrNoSeed is returned when an Anonymizer is created without a seed
+var ErrNoSeed = errors.New("anonymizer seed must not be empty")
+
+// Kinds of ID an Anonymizer replaces; each kind is the prefix of its pseudonyms
+const (
+	anonTruck  = "truck"
+	anonDriver = "driver"
+	anonRoute  = "route"
+	anonYard   = "yard"
+)
+
+// locationPrecision is the number of decimals kept of an anonymized
+// coordinate; one decimal is about 11 km at the equator
+const locationPrecision = 1
+
+// Anonymizer produces copies of fleet data with pseudonymous IDs and scrubbed
+// personal data. Pseudonyms are derived from the seed with HMAC-SHA256, so
+// the same seed maps an ID to the same pseudonym in every run and every file,
+// and references between trucks and drivers stay consistent. Numbers, tags
+// and versions are copied as they are, so the result has the shape of the
+// original.
+type Anonymizer struct {
+	key []byte
+	// Keep lists custom fields copied verbatim; every other custom value is
+	// replaced with a pseudonym
+	Keep []string
+}
+
+// NewAnonymizer creates an Anonymizer for seed. Anyone who knows the seed
+// can confirm a guess of an original ID, so treat it as a secret.
+func NewAnonymizer(seed string) (*Anonymizer, error) {
+	if seed == "" {
+		return nil, ErrNoSeed
+	}
+	return &Anonymizer{key: []byte(seed)}, nil
+}
+
+// ID returns the pseudonym of an ID of the given kind. The empty ID stays empty.
+func (a *Anonymizer) ID(kind, id string) string {
+	if id == "" {
+		return ""
+	}
+	return kind + "-" + a.hash(kind, id)
+}
+
+// hash returns the leading 12 hex digits of the HMAC of kind and value
+func (a *Anonymizer) hash(kind, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// Truck returns an anonymized copy of t
+func (a *Anonymizer) Truck(t Truck) Truck {
+	c := t.clone()
+	c.ID = a.ID(anonTruck, t.ID)
+	c.DriverID = a.ID(anonDriver, t.DriverID)
+	c.RouteID = a.ID(anonRoute, t.RouteID)
+	c.YardID = a.ID(anonYard, t.YardID)
+	a.scrubCustom(c.Custom)
+	if c.Location != nil {
+		c.Location.Lat = roundCoordinate(c.Location.Lat)
+		c.Location.Lon = roundCoordinate(c.Location.Lon)
+	}
+	return c
+}
+
+// Driver returns an anonymized copy of d. The name is replaced as well as the ID.
+func (a *Anonymizer) Driver(d Driver) Driver {
+	c := d.clone()
+	c.ID = a.ID(anonDriver, d.ID)
+	c.TruckID = a.ID(anonTruck, d.TruckID)
+	if d.Name != "" {
+		c.Name = "Driver " + a.hash("name", d.Name)
+	}
+	a.scrubCustom(c.Custom)
+	return c
+}
+
+// Trucks returns anonymized copies of trucks, in the same order
+func (a *Anonymizer) Trucks(trucks []Truck) []Truck {
+	out := make([]Truck, len(trucks))
+	for i, t := range trucks {
+		out[i] = a.Truck(t)
+	}
+	return out
+}
+
+// scrubCustom replaces the custom values not kept. Equal values get equal
+// pseudonyms, so grouping by a field still works on the copy.
+func (a *Anonymizer) scrubCustom(custom map[string]string) {
+	for name, value := range custom {
+		if !slices.Contains(a.Keep, name) && value != "" {
+			custom[name] = a.hash(csvCustomPrefix+name, value)
+		}
+	}
+}
+
+// roundCoordinate coarsens a coordinate to locationPrecision decimals
+func roundCoordinate(v float64) float64 {
+	scale := math.Pow10(locationPrecision)
+	return math.Round(v*scale) / scale
+}
+
+// AnonymizedSnapshot returns anonymized copies of every truck and driver,
+// taken under one lock so the references between them are consistent
+func (tm *truckManager) AnonymizedSnapshot(a *Anonymizer) ([]Truck, []Driver) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	trucks := make([]Truck, 0, len(tm.ids))
+	for _, id := range tm.ids {
+		trucks = append(trucks, a.Truck(*tm.trucks[id].load()))
+	}
+	drivers := make([]Driver, 0, len(tm.drivers))
+	for _, d := range tm.drivers {
+		drivers = append(drivers, a.Driver(*d))
+	}
+	slices.SortFunc(drivers, func(x, y Driver) int { return strings.Compare(x.ID, y.ID) })
+	return trucks, drivers
+}
+
+// runAnonymizeCommand writes an anonymized copy of a fleet export as JSON
+func runAnonymizeCommand(args []string) error {
+	fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+	seed := fs.String("seed", "", "secret the pseudonyms are derived from; reuse it to get the same pseudonyms")
+	keep := fs.String("keep", "", "comma-separated custom fields to copy verbatim")
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: anonymize -seed secret [-keep fields] [-o file] <export>")
+	}
+
+	a, err := NewAnonymizer(*seed)
+	if err != nil {
+		return err
+	}
+	if *keep != "" {
+		a.Keep = strings.Split(*keep, ",")
+	}
+	trucks, err := readSnapshot(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(jsonFleetFile{Trucks: a.Trucks(trucks)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return writeFileAtomic(*out, data)
+}
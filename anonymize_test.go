@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymizerDeterministic(t *testing.T) {
+	first, _ := NewAnonymizer("secret")
+	second, _ := NewAnonymizer("secret")
+	other, _ := NewAnonymizer("other")
+
+	id := first.ID(anonTruck, "TRK-001")
+	if id != second.ID(anonTruck, "TRK-001") {
+		t.Errorf("Expected the same pseudonym for the same seed, got %s and %s", id, second.ID(anonTruck, "TRK-001"))
+	}
+	if id == other.ID(anonTruck, "TRK-001") {
+		t.Errorf("Expected a different pseudonym for another seed, got %s for both", id)
+	}
+	if !strings.HasPrefix(id, "truck-") || strings.Contains(id, "TRK-001") {
+		t.Errorf("Expected a truck pseudonym hiding the original, got %s", id)
+	}
+	if first.ID(anonDriver, "TRK-001") == id {
+		t.Errorf("Expected kinds to get distinct pseudonyms")
+	}
+	if first.ID(anonTruck, "") != "" {
+		t.Errorf("Expected the empty ID to stay empty")
+	}
+}
+
+func TestNewAnonymizerNoSeed(t *testing.T) {
+	if _, err := NewAnonymizer(""); !errors.Is(err, ErrNoSeed) {
+		t.Errorf("Expected no seed error, got %v", err)
+	}
+}
+
+func TestAnonymizeTruck(t *testing.T) {
+	a, _ := NewAnonymizer("secret")
+	a.Keep = []string{"model"}
+	original := Truck{
+		ID: "1", Capacity: 100, CurrentLoad: 40, DriverID: "d1", RouteID: "r1", Version: 3,
+		Custom:   map[string]string{"model": "FH16", "vin": "YV2RT40A5GB123456"},
+		Tags:     map[string]string{"region": "west"},
+		Location: &Location{Lat: 59.33258, Lon: 18.06490, Time: time.Unix(100, 0)},
+	}
+
+	anon := a.Truck(original)
+	if anon.ID != a.ID(anonTruck, "1") || anon.DriverID != a.ID(anonDriver, "d1") || anon.RouteID != a.ID(anonRoute, "r1") {
+		t.Errorf("Expected pseudonymous IDs, got %+v", anon)
+	}
+	if anon.YardID != "" {
+		t.Errorf("Expected empty yard to stay empty, got %s", anon.YardID)
+	}
+	if anon.Capacity != 100 || anon.CurrentLoad != 40 || anon.Version != 3 || anon.Tags["region"] != "west" {
+		t.Errorf("Expected numbers and tags copied, got %+v", anon)
+	}
+	if anon.Custom["model"] != "FH16" {
+		t.Errorf("Expected kept field copied, got %s", anon.Custom["model"])
+	}
+	if vin := anon.Custom["vin"]; vin == "" || vin == "YV2RT40A5GB123456" {
+		t.Errorf("Expected vin scrubbed, got %s", vin)
+	}
+	if anon.Location.Lat != 59.3 || anon.Location.Lon != 18.1 {
+		t.Errorf("Expected coarsened location, got %+v", anon.Location)
+	}
+
+	// The original is untouched
+	if original.Custom["vin"] != "YV2RT40A5GB123456" || original.Location.Lat != 59.33258 {
+		t.Errorf("Expected original unchanged, got %+v", original)
+	}
+}
+
+func TestAnonymizedSnapshot(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Ada Lovelace")
+	manager.AssignDriver("1", "d1")
+
+	a, _ := NewAnonymizer("secret")
+	trucks, drivers := manager.AnonymizedSnapshot(a)
+	if len(trucks) != 1 || len(drivers) != 1 {
+		t.Fatalf("Expected 1 truck and 1 driver, got %d and %d", len(trucks), len(drivers))
+	}
+	if trucks[0].DriverID != drivers[0].ID || drivers[0].TruckID != trucks[0].ID {
+		t.Errorf("Expected consistent references, got truck %+v and driver %+v", trucks[0], drivers[0])
+	}
+	if strings.Contains(drivers[0].Name, "Ada") {
+		t.Errorf("Expected driver name scrubbed, got %s", drivers[0].Name)
+	}
+}
+
+func TestRunAnonymizeCommand(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "fleet.json")
+	out := filepath.Join(dir, "anon.json")
+	os.WriteFile(in, []byte(`{"trucks": [{"id": "1", "capacity": 100, "driver_id": "d1", "version": 1}]}`), 0o644)
+
+	if err := runAnonymizeCommand([]string{"-seed", "secret", "-o", out, in}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	trucks, err := readSnapshot(out)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	a, _ := NewAnonymizer("secret")
+	if len(trucks) != 1 || trucks[0].ID != a.ID(anonTruck, "1") || trucks[0].Capacity != 100 {
+		t.Errorf("Expected anonymized truck, got %+v", trucks)
+	}
+
+	if err := runAnonymizeCommand([]string{in}); !errors.Is(err, ErrNoSeed) {
+		t.Errorf("Expected no seed error, got %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Errors for dock appointments
+var (
+	ErrDockNotFound        = errors.New("dock not found")
+	ErrDockExist           = errors.New("dock already exist")
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrInvalidAppointment  = errors.New("invalid appointment")
+	ErrDockBooked          = errors.New("dock already booked")
+)
+
+// AppointmentStatus is where an appointment is in its lifecycle
+type AppointmentStatus string
+
+// Appointment statuses. Only booked appointments hold the dock.
+const (
+	AppointmentBooked    AppointmentStatus = "booked"
+	AppointmentCompleted AppointmentStatus = "completed"
+	AppointmentNoShow    AppointmentStatus = "no_show"
+	AppointmentCancelled AppointmentStatus = "cancelled"
+)
+
+// Appointment is a truck's booking of a dock for [Start, End)
+type Appointment struct {
+	ID      int
+	DockID  string
+	TruckID string
+	Start   time.Time
+	End     time.Time
+	Status  AppointmentStatus
+}
+
+// overlaps reports whether the appointment's slot intersects [start, end)
+func (a *Appointment) overlaps(start, end time.Time) bool {
+	return a.Start.Before(end) && start.Before(a.End)
+}
+
+// dock is a loading dock in a yard and its appointments, ordered by start
+type dock struct {
+	id           string
+	yardID       string
+	appointments []*Appointment
+}
+
+// AddDock registers a loading dock in a yard. Docks and their appointments
+// are held in memory only.
+func (tm *truckManager) AddDock(yardID, dockID string) error {
+	if yardID == "" || dockID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.yards[yardID]; !exist {
+		return ErrYardNotFound
+	}
+	if _, exist := tm.docks[dockID]; exist {
+		return ErrDockExist
+	}
+	tm.docks[dockID] = &dock{id: dockID, yardID: yardID}
+	return nil
+}
+
+// BookAppointment books a dock for a truck over [start, end). The slot must
+// not overlap another booked appointment on the dock, and the truck must not
+// be under maintenance when the slot starts.
+func (tm *truckManager) BookAppointment(dockID, truckID string, start, end time.Time) (Appointment, error) {
+	if dockID == "" || truckID == "" {
+		return Appointment{}, ErrEmptyID
+	}
+	if !end.After(start) {
+		return Appointment{}, fmt.Errorf("%w: end must be after start", ErrInvalidAppointment)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	d, exist := tm.docks[dockID]
+	if !exist {
+		return Appointment{}, ErrDockNotFound
+	}
+	if _, exist := tm.trucks[truckID]; !exist {
+		return Appointment{}, ErrTruckNotFound
+	}
+	if err := tm.checkSlotLocked(d, nil, truckID, start, end); err != nil {
+		return Appointment{}, err
+	}
+
+	tm.nextAppointmentID++
+	a := &Appointment{ID: tm.nextAppointmentID, DockID: dockID, TruckID: truckID, Start: start, End: end, Status: AppointmentBooked}
+	tm.appointments[a.ID] = a
+	d.appointments = append(d.appointments, a)
+	d.sort()
+	return *a, nil
+}
+
+// RescheduleAppointment moves a booked appointment to [start, end) on the same dock
+func (tm *truckManager) RescheduleAppointment(id int, start, end time.Time) (Appointment, error) {
+	if !end.After(start) {
+		return Appointment{}, fmt.Errorf("%w: end must be after start", ErrInvalidAppointment)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	a, err := tm.bookedAppointmentLocked(id)
+	if err != nil {
+		return Appointment{}, err
+	}
+	d := tm.docks[a.DockID]
+	if err := tm.checkSlotLocked(d, a, a.TruckID, start, end); err != nil {
+		return Appointment{}, err
+	}
+
+	a.Start, a.End = start, end
+	d.sort()
+	return *a, nil
+}
+
+// CancelAppointment frees the dock held by a booked appointment
+func (tm *truckManager) CancelAppointment(id int) error {
+	return tm.closeAppointment(id, AppointmentCancelled)
+}
+
+// CompleteAppointment records that the truck turned up for its appointment
+func (tm *truckManager) CompleteAppointment(id int) error {
+	return tm.closeAppointment(id, AppointmentCompleted)
+}
+
+// MarkNoShow records that the truck missed its appointment
+func (tm *truckManager) MarkNoShow(id int) error {
+	return tm.closeAppointment(id, AppointmentNoShow)
+}
+
+// closeAppointment moves a booked appointment to a final status
+func (tm *truckManager) closeAppointment(id int, status AppointmentStatus) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	a, err := tm.bookedAppointmentLocked(id)
+	if err != nil {
+		return err
+	}
+	a.Status = status
+	return nil
+}
+
+// GetAppointment retrieves an appointment by ID
+func (tm *truckManager) GetAppointment(id int) (Appointment, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	a, exist := tm.appointments[id]
+	if !exist {
+		return Appointment{}, ErrAppointmentNotFound
+	}
+	return *a, nil
+}
+
+// DockSchedule returns a dock's appointments that intersect [from, to),
+// in any status, ordered by start
+func (tm *truckManager) DockSchedule(dockID string, from, to time.Time) ([]Appointment, error) {
+	if dockID == "" {
+		return nil, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	d, exist := tm.docks[dockID]
+	if !exist {
+		return nil, ErrDockNotFound
+	}
+	schedule := []Appointment{}
+	for _, a := range d.appointments {
+		if a.overlaps(from, to) {
+			schedule = append(schedule, *a)
+		}
+	}
+	return schedule, nil
+}
+
+// NoShows returns how many appointments the truck has missed
+func (tm *truckManager) NoShows(truckID string) int {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	n := 0
+	for _, a := range tm.appointments {
+		if a.TruckID == truckID && a.Status == AppointmentNoShow {
+			n++
+		}
+	}
+	return n
+}
+
+// bookedAppointmentLocked returns the appointment if it is still booked. Callers must hold the lock.
+func (tm *truckManager) bookedAppointmentLocked(id int) (*Appointment, error) {
+	a, exist := tm.appointments[id]
+	if !exist {
+		return nil, ErrAppointmentNotFound
+	}
+	if a.Status != AppointmentBooked {
+		return nil, fmt.Errorf("%w: appointment %d is %s", ErrInvalidAppointment, id, a.Status)
+	}
+	return a, nil
+}
+
+// checkSlotLocked returns an error if [start, end) clashes with another
+// booked appointment on the dock or with any of the truck's maintenance.
+// self is the appointment being moved, if any. Callers must hold the lock.
+func (tm *truckManager) checkSlotLocked(d *dock, self *Appointment, truckID string, start, end time.Time) error {
+	for _, a := range d.appointments {
+		if a != self && a.Status == AppointmentBooked && a.overlaps(start, end) {
+			return fmt.Errorf("%w: %s is booked by %s until %s", ErrDockBooked, d.id, a.TruckID, a.End.Format(time.RFC3339))
+		}
+	}
+	for _, w := range tm.maintenance[truckID] {
+		if w.overlaps(start, end) {
+			return fmt.Errorf("%w: %s is in maintenance from %s to %s", ErrTruckUnavailable, truckID, w.Start.Format(time.RFC3339), w.End.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// bookedAppointmentsLocked returns the booked appointments of a truck.
+// Callers must hold the lock.
+func (tm *truckManager) bookedAppointmentsLocked(truckID string) []*Appointment {
+	var booked []*Appointment
+	for _, a := range tm.appointments {
+		if a.TruckID == truckID && a.Status == AppointmentBooked {
+			booked = append(booked, a)
+		}
+	}
+	return booked
+}
+
+// cancelAppointmentsLocked cancels the booked appointments of a truck that
+// is leaving the fleet. Callers must hold the write lock.
+func (tm *truckManager) cancelAppointmentsLocked(truckID string) {
+	for _, a := range tm.appointments {
+		if a.TruckID == truckID && a.Status == AppointmentBooked {
+			a.Status = AppointmentCancelled
+		}
+	}
+}
+
+// sort orders the dock's appointments by start
+func (d *dock) sort() {
+	sort.Slice(d.appointments, func(i, j int) bool { return d.appointments[i].Start.Before(d.appointments[j].Start) })
+}
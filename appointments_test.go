@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newDockManager returns a manager with trucks 1 and 2 and dock d1 in yard y1
+func newDockManager() *truckManager {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddYard(Yard{ID: "y1", Slots: 4})
+	manager.AddDock("y1", "d1")
+	return manager
+}
+
+func TestBookAppointment(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+
+	a, err := manager.BookAppointment("d1", "1", start, start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if a.ID == 0 || a.Status != AppointmentBooked {
+		t.Errorf("Expected a numbered booked appointment, got %+v", a)
+	}
+
+	if _, err := manager.BookAppointment("d1", "2", start.Add(15*time.Minute), start.Add(45*time.Minute)); !errors.Is(err, ErrDockBooked) {
+		t.Errorf("Expected dock booked error, got %v", err)
+	}
+	if _, err := manager.BookAppointment("d1", "2", start.Add(30*time.Minute), start.Add(time.Hour)); err != nil {
+		t.Errorf("Expected back-to-back slots to be allowed, got %v", err)
+	}
+}
+
+func TestBookAppointmentErrors(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+
+	if _, err := manager.BookAppointment("d2", "1", start, start.Add(time.Hour)); err != ErrDockNotFound {
+		t.Errorf("Expected dock not found error, got %v", err)
+	}
+	if _, err := manager.BookAppointment("d1", "3", start, start.Add(time.Hour)); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if _, err := manager.BookAppointment("d1", "1", start, start); !errors.Is(err, ErrInvalidAppointment) {
+		t.Errorf("Expected invalid appointment error, got %v", err)
+	}
+
+	manager.ScheduleMaintenance("1", start.Add(-time.Minute), start.Add(time.Hour), "service")
+	if _, err := manager.BookAppointment("d1", "1", start, start.Add(time.Hour)); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected truck unavailable error, got %v", err)
+	}
+	manager.ScheduleMaintenance("2", start.Add(30*time.Minute), start.Add(2*time.Hour), "service")
+	if _, err := manager.BookAppointment("d1", "2", start, start.Add(time.Hour)); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected maintenance starting mid-slot to make the truck unavailable, got %v", err)
+	}
+	if err := manager.AddDock("y2", "d9"); err != ErrYardNotFound {
+		t.Errorf("Expected yard not found error, got %v", err)
+	}
+}
+
+func TestRescheduleAppointment(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+	first, _ := manager.BookAppointment("d1", "1", start, start.Add(time.Hour))
+	manager.BookAppointment("d1", "2", start.Add(2*time.Hour), start.Add(3*time.Hour))
+
+	// Moving within its own slot is fine
+	if _, err := manager.RescheduleAppointment(first.ID, start.Add(30*time.Minute), start.Add(90*time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := manager.RescheduleAppointment(first.ID, start.Add(2*time.Hour), start.Add(3*time.Hour)); !errors.Is(err, ErrDockBooked) {
+		t.Errorf("Expected dock booked error, got %v", err)
+	}
+
+	schedule, _ := manager.DockSchedule("d1", start, start.Add(4*time.Hour))
+	if len(schedule) != 2 || schedule[0].ID != first.ID || !schedule[0].Start.Equal(start.Add(30*time.Minute)) {
+		t.Errorf("Expected the moved appointment first, got %+v", schedule)
+	}
+}
+
+func TestAppointmentLifecycle(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+	a, _ := manager.BookAppointment("d1", "1", start, start.Add(time.Hour))
+
+	if err := manager.MarkNoShow(a.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := manager.NoShows("1"); n != 1 {
+		t.Errorf("Expected 1 no-show, got %d", n)
+	}
+	if err := manager.CompleteAppointment(a.ID); !errors.Is(err, ErrInvalidAppointment) {
+		t.Errorf("Expected a closed appointment to be final, got %v", err)
+	}
+
+	// A no-show frees the dock
+	if _, err := manager.BookAppointment("d1", "2", start, start.Add(time.Hour)); err != nil {
+		t.Errorf("Expected the slot to be free again, got %v", err)
+	}
+	if err := manager.CancelAppointment(99); err != ErrAppointmentNotFound {
+		t.Errorf("Expected appointment not found error, got %v", err)
+	}
+}
+
+func TestRemoveTruckCancelsAppointments(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+	a, _ := manager.BookAppointment("d1", "1", start, start.Add(time.Hour))
+
+	manager.RemoveTruck("1")
+	got, _ := manager.GetAppointment(a.ID)
+	if got.Status != AppointmentCancelled {
+		t.Errorf("Expected cancelled appointment, got %s", got.Status)
+	}
+}
+
+func TestRemoveTrucksRollbackRebooksAppointments(t *testing.T) {
+	manager := newDockManager()
+	start := time.Now().Add(time.Hour)
+	a, _ := manager.BookAppointment("d1", "1", start, start.Add(time.Hour))
+
+	if _, err := manager.RemoveTrucks([]string{"1", "missing"}, AllOrNothing()); err == nil {
+		t.Fatalf("Expected the batch to fail")
+	}
+	got, _ := manager.GetAppointment(a.ID)
+	if got.Status != AppointmentBooked {
+		t.Errorf("Expected appointment booked again after rollback, got %s", got.Status)
+	}
+}
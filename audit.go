@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// actorKey is the context key for the caller making a change
+type actorKey struct{}
+
+// WithActor returns a copy of ctx naming who is making changes. Mutations
+// made with the returned context record the actor in the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, if any
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	return actor, ok && actor != ""
+}
+
+// AuditEntry records one change to a truck. Before is nil for an add and
+// After is nil for a remove.
+type AuditEntry struct {
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Actor     string    `json:"actor,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Op        string    `json:"op"`
+	TruckID   string    `json:"truck_id"`
+	Before    *Truck    `json:"before,omitempty"`
+	After     *Truck    `json:"after,omitempty"`
+}
+
+// AuditLog keeps every change made through a manager, oldest first. It is
+// safe for concurrent use.
+type AuditLog struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+	w       io.Writer
+	enc     *json.Encoder
+	err     error
+}
+
+// NewAuditLog returns an empty audit log. If w is not nil every entry is
+// also written to it as a line of JSON, so the trail can outlive the
+// process; the in-memory copy is lost on restart.
+func NewAuditLog(w io.Writer) *AuditLog {
+	l := &AuditLog{w: w}
+	if w != nil {
+		l.enc = json.NewEncoder(w)
+	}
+	return l
+}
+
+// WithAuditLog records every change to a truck in l: adds, updates, loads,
+// unloads and removes, including those made by batches, upserts and
+// imports, as well as tag, field, driver, route, yard, fuel and location
+// changes. Those last ones carry no actor or request ID, since the methods
+// making them take no context.
+func WithAuditLog(l *AuditLog) Option {
+	return func(o *managerOptions) {
+		o.audit = l
+	}
+}
+
+// append adds an entry, filling in its sequence number
+func (l *AuditLog) append(e AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e.Seq = uint64(len(l.entries)) + 1
+	l.entries = append(l.entries, e)
+	if l.enc != nil && l.err == nil {
+		l.err = l.enc.Encode(e)
+	}
+}
+
+// Err returns the first error writing entries to the log's writer. Entries
+// are still kept in memory after a write fails.
+func (l *AuditLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// auditLocked records a successful change. Callers must hold the write
+// lock, or the read lock and the truck's shard lock, so entries for a truck
+// are in the order its changes were made.
+func (tm *truckManager) auditLocked(ctx context.Context, op, id string, before, after *Truck) {
+	if tm.audit == nil {
+		return
+	}
+	e := AuditEntry{Time: time.Now(), Op: op, TruckID: id, Before: before, After: after}
+	e.Actor, _ = ActorFromContext(ctx)
+	e.RequestID, _ = RequestIDFromContext(ctx)
+	tm.audit.append(e)
+}
+
+// auditChangeLocked records a change made by one of the set*Locked helpers.
+// old and updated are copied only when someone keeps the entry. While
+// auditHeld is set the caller audits the change itself and nothing is
+// recorded here. Callers must hold the lock as for auditLocked.
+func (tm *truckManager) auditChangeLocked(op, id string, old, updated *Truck) {
+	if tm.audit == nil || tm.auditHeld {
+		return
+	}
+	tm.auditLocked(context.Background(), op, id, clonePtr(old), clonePtr(updated))
+}
+
+// GetAuditLog returns up to limit entries for the truck starting at offset,
+// oldest first, skipping entries made before since. An empty truckID
+// returns entries for every truck. Without WithAuditLog there are no entries.
+func (tm *truckManager) GetAuditLog(truckID string, since time.Time, offset, limit int) ([]AuditEntry, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, ErrInvalidPage
+	}
+	if tm.audit == nil {
+		return nil, nil
+	}
+
+	l := tm.audit
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var page []AuditEntry
+	skipped := 0
+	for _, e := range l.entries {
+		if e.Time.Before(since) || (truckID != "" && e.TruckID != truckID) {
+			continue
+		}
+		if skipped < offset {
+			skipped++
+			continue
+		}
+		e.Before, e.After = clonePtr(e.Before), clonePtr(e.After)
+		page = append(page, e)
+		if len(page) == limit {
+			break
+		}
+	}
+	return page, nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRecordsMutations(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	ctx := WithRequestID(WithActor(context.Background(), "amina"), "req-1")
+
+	manager.AddTruckContext(ctx, "1", 100)
+	manager.UpdateTruckCargoContext(ctx, "1", 200)
+	manager.LoadCargoContext(ctx, "1", 50)
+	manager.RemoveTruckContext(ctx, "1")
+	// Failed changes are not audited
+	manager.RemoveTruckContext(ctx, "1")
+
+	entries, err := manager.GetAuditLog("1", time.Time{}, 0, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ops := []string{"add", "update", "load", "remove"}
+	if len(entries) != len(ops) {
+		t.Fatalf("Expected %d entries, got %+v", len(ops), entries)
+	}
+	for i, e := range entries {
+		if e.Op != ops[i] || e.Seq != uint64(i+1) || e.Actor != "amina" || e.RequestID != "req-1" || e.TruckID != "1" {
+			t.Errorf("Expected %s #%d by amina in req-1, got %+v", ops[i], i+1, e)
+		}
+	}
+	if entries[0].Before != nil || entries[0].After.Capacity != 100 {
+		t.Errorf("Expected the add to have only an after value, got %+v", entries[0])
+	}
+	if entries[1].Before.Capacity != 100 || entries[1].After.Capacity != 200 {
+		t.Errorf("Expected capacity 100 then 200, got %+v", entries[1])
+	}
+	if entries[3].Before.CurrentLoad != 50 || entries[3].After != nil {
+		t.Errorf("Expected the remove to have only a before value, got %+v", entries[3])
+	}
+}
+
+func TestAuditLogPagination(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	for i := 0; i < 4; i++ {
+		manager.LoadCargo("1", 1)
+	}
+
+	page, _ := manager.GetAuditLog("1", time.Time{}, 1, 2)
+	if len(page) != 2 || page[0].Op != "load" || page[0].Seq != 3 || page[1].Seq != 4 {
+		t.Errorf("Expected entries 3 and 4, got %+v", page)
+	}
+	page, _ = manager.GetAuditLog("1", time.Time{}, 4, 10)
+	if len(page) != 1 || page[0].Seq != 6 {
+		t.Errorf("Expected only entry 6, got %+v", page)
+	}
+	all, _ := manager.GetAuditLog("", time.Time{}, 0, 100)
+	if len(all) != 6 {
+		t.Errorf("Expected 6 entries across the fleet, got %d", len(all))
+	}
+	if _, err := manager.GetAuditLog("1", time.Time{}, -1, 10); err != ErrInvalidPage {
+		t.Errorf("Expected ErrInvalidPage, got %v", err)
+	}
+
+	later, _ := manager.GetAuditLog("", time.Now().Add(time.Hour), 0, 10)
+	if len(later) != 0 {
+		t.Errorf("Expected no entries in the future, got %+v", later)
+	}
+}
+
+func TestAuditLogBatches(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTrucks([]Truck{{ID: "1", Capacity: 10}, {ID: "2", Capacity: 20}})
+	// A rolled-back batch leaves no trace
+	manager.AddTrucks([]Truck{{ID: "3", Capacity: 10}, {ID: "1", Capacity: 10}}, AllOrNothing())
+	manager.UpsertTruck("1", NewUpdateSpec().WithCapacity(30))
+	manager.CreateTruck(5)
+
+	entries, _ := manager.GetAuditLog("", time.Time{}, 0, 10)
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %+v", entries)
+	}
+	if entries[0].Op != "add" || entries[1].TruckID != "2" || entries[2].Op != "update" || entries[2].After.Capacity != 30 || entries[3].Op != "add" {
+		t.Errorf("Expected two adds, an update and a created truck, got %+v", entries)
+	}
+}
+
+func TestAuditLogImport(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTruck("old", 100)
+	manager.AddTruck("kept", 100)
+
+	input := `{"trucks": [{"id": "kept", "capacity": 150}, {"id": "new", "capacity": 50}]}`
+	manager.ImportFleet(strings.NewReader(input), FormatJSON, MergeReplace)
+
+	entries, _ := manager.GetAuditLog("", time.Time{}, 2, 10)
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 import entries, got %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Op != "import" {
+			t.Errorf("Expected import entries, got %+v", e)
+		}
+	}
+	if entries[2].TruckID != "old" || entries[2].After != nil {
+		t.Errorf("Expected the dropped truck last, got %+v", entries[2])
+	}
+}
+
+func TestAuditLogRecordsEveryChange(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Ada")
+	manager.CreateRoute(Route{ID: "r1", Origin: "Nairobi", Destination: "Mombasa", DistanceKm: 480})
+	manager.AddYard(Yard{ID: "y1", Slots: 2})
+	manager.DefineField(TruckEntity, FieldDef{Name: "plate", Type: FieldString})
+
+	manager.SetTag("1", "region", "west")
+	manager.RemoveTag("1", "region")
+	manager.SetTruckField("1", "plate", "KAA 123A")
+	manager.AssignDriver("1", "d1")
+	manager.UnassignDriver("1")
+	manager.AssignRoute("1", "r1")
+	manager.UnassignRoute("1")
+	manager.CheckIn("y1", "1")
+	manager.CheckOut("1")
+	manager.SetFuelTank("1", 400)
+	manager.RecordRefuel("1", 100, 150)
+	manager.RecordTrip("1", 200, 60)
+	manager.UpdateLocation("1", -1.29, 36.82, time.Now())
+
+	entries, _ := manager.GetAuditLog("1", time.Time{}, 0, 100)
+	ops := []string{"add", "tag", "tag", "field", "driver", "driver", "route", "route", "yard", "yard", "fuel", "fuel", "fuel", "location"}
+	if len(entries) != len(ops) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(ops), len(entries), entries)
+	}
+	for i, e := range entries {
+		if e.Op != ops[i] {
+			t.Errorf("Expected entry %d to be %s, got %s", i+1, ops[i], e.Op)
+		}
+	}
+	if last := entries[len(entries)-1]; last.After.Location == nil || last.Before.Location != nil {
+		t.Errorf("Expected the location entry to carry the new position, got %+v", last)
+	}
+}
+
+func TestAuditLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewAuditLog(&buf)
+	manager := NewFleetManager(WithAuditLog(log))
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("1", 10)
+
+	scanner := bufio.NewScanner(&buf)
+	lines := 0
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Expected JSON lines, got %v", err)
+		}
+		lines++
+	}
+	if lines != 2 || log.Err() != nil {
+		t.Errorf("Expected 2 lines and no error, got %d and %v", lines, log.Err())
+	}
+}
+
+func TestAuditLogEntriesAreCopies(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTruck("1", 100)
+
+	entries, _ := manager.GetAuditLog("1", time.Time{}, 0, 1)
+	entries[0].After.Capacity = 0
+	entries, _ = manager.GetAuditLog("1", time.Time{}, 0, 1)
+	if entries[0].After.Capacity != 100 {
+		t.Errorf("Expected the entry to be unchanged, got %+v", entries[0].After)
+	}
+}
+
+func TestNoAuditLog(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	entries, err := manager.GetAuditLog("1", time.Time{}, 0, 10)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries, got %+v and %v", entries, err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// LaneBalancer spreads work across the trucks serving a recurring lane.
+// It uses smooth weighted round-robin with each truck's remaining capacity
+// as its weight: over any window, trucks are chosen in proportion to the
+// room they have left, and a truck that was just chosen waits until the
+// others have caught up, so recent use is accounted for without a separate
+// counter.
+type LaneBalancer struct {
+	manager *truckManager
+	trucks  []string
+	mu      sync.Mutex
+	current map[string]int
+}
+
+// NewLaneBalancer creates a balancer over the given trucks
+func NewLaneBalancer(manager *truckManager, truckIDs []string) *LaneBalancer {
+	return &LaneBalancer{
+		manager: manager,
+		trucks:  append([]string(nil), truckIDs...),
+		current: make(map[string]int),
+	}
+}
+
+// Next returns the truck that should take the next job on the lane. Trucks
+// that have left the fleet, are full or are under maintenance are
+// skipped.
+func (b *LaneBalancer) Next() (Truck, error) {
+	found, _, err := b.manager.GetTrucks(b.trucks)
+	if err != nil {
+		return Truck{}, err
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := 0
+	var chosen *Truck
+	for i := range found {
+		truck := &found[i]
+		weight := truck.Capacity - truck.CurrentLoad
+		if weight <= 0 || b.manager.CheckAvailable(truck.ID, now) != nil {
+			continue
+		}
+		b.current[truck.ID] += weight
+		total += weight
+		if chosen == nil || b.current[truck.ID] > b.current[chosen.ID] {
+			chosen = truck
+		}
+	}
+
+	if chosen == nil {
+		return Truck{}, ErrNoTruckAvailable
+	}
+	b.current[chosen.ID] -= total
+	return *chosen, nil
+}
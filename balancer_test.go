@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestLaneBalancerProportional(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("big", 300)
+	manager.AddTruck("small", 100)
+	balancer := NewLaneBalancer(manager, []string{"big", "small"})
+
+	counts := make(map[string]int)
+	for i := 0; i < 40; i++ {
+		truck, err := balancer.Next()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		counts[truck.ID]++
+	}
+
+	if counts["big"] != 30 || counts["small"] != 10 {
+		t.Errorf("Expected a 3:1 split, got %v", counts)
+	}
+}
+
+func TestLaneBalancerSpreadsConsecutivePicks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("a", 100)
+	manager.AddTruck("b", 100)
+	balancer := NewLaneBalancer(manager, []string{"a", "b"})
+
+	first, _ := balancer.Next()
+	second, _ := balancer.Next()
+	if first.ID == second.ID {
+		t.Errorf("Expected equal trucks to alternate, got %s twice", first.ID)
+	}
+}
+
+func TestLaneBalancerSkipsUnusableTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("empty", 0)
+	manager.AddTruck("ok", 100)
+	balancer := NewLaneBalancer(manager, []string{"empty", "gone", "ok"})
+
+	truck, err := balancer.Next()
+	if err != nil || truck.ID != "ok" {
+		t.Errorf("Expected truck ok, got %+v, %v", truck, err)
+	}
+
+	manager.RemoveTruck("ok")
+	if _, err := balancer.Next(); err != ErrNoTruckAvailable {
+		t.Errorf("Expected no truck available error, got %v", err)
+	}
+}
+
+func TestLaneBalancerUsesRemainingCapacity(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("a", 1000)
+	manager.AddTruck("b", 1000)
+	manager.LoadCargo("a", 750)
+	balancer := NewLaneBalancer(manager, []string{"a", "b"})
+
+	counts := make(map[string]int)
+	for i := 0; i < 40; i++ {
+		truck, _ := balancer.Next()
+		counts[truck.ID]++
+	}
+	if counts["a"] != 8 || counts["b"] != 32 {
+		t.Errorf("Expected a 1:4 split by remaining capacity, got %v", counts)
+	}
+
+	manager.LoadCargo("a", 250)
+	for i := 0; i < 5; i++ {
+		if truck, _ := balancer.Next(); truck.ID != "b" {
+			t.Errorf("Expected a full truck to be skipped, got %s", truck.ID)
+		}
+	}
+}
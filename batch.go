@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrBatchAborted is returned when an all-or-nothing batch fails and its changes are rolled back
+var ErrBatchAborted = errors.New("batch aborted")
+
+// BatchItemError records why one item of a batch failed
+type BatchItemError struct {
+	ID  string
+	Err error
+}
+
+// BatchResult reports the outcome of each item in a batch
+type BatchResult struct {
+	Succeeded []string         // IDs whose change was applied
+	Failed    []BatchItemError // items that were rejected
+}
+
+// BatchOption configures how a batch is applied
+type BatchOption func(*batchConfig)
+
+// batchConfig collects the settings applied by BatchOptions
+type batchConfig struct {
+	allOrNothing bool
+}
+
+// AllOrNothing makes the batch atomic: the first failing item rolls back
+// every change already made and the batch returns ErrBatchAborted.
+func AllOrNothing() BatchOption {
+	return func(c *batchConfig) {
+		c.allOrNothing = true
+	}
+}
+
+// batchItem is one change in a batch, together with how to undo it. op
+// names the change in the audit log.
+type batchItem struct {
+	id    string
+	op    string
+	apply func() (undo func() error, err error)
+}
+
+// AddTrucks adds several trucks under one lock acquisition
+func (tm *truckManager) AddTrucks(trucks []Truck, opts ...BatchOption) (BatchResult, error) {
+	items := make([]batchItem, 0, len(trucks))
+	for _, t := range trucks {
+		items = append(items, tm.addItem(t.clone()))
+	}
+	return tm.applyBatch("add", items, opts)
+}
+
+// addItem is a batch item adding truck
+func (tm *truckManager) addItem(truck Truck) batchItem {
+	return batchItem{id: truck.ID, op: "add", apply: func() (func() error, error) {
+		if err := tm.addLocked(truck); err != nil {
+			return nil, err
+		}
+		return func() error {
+			_, err := tm.removeLocked(truck.ID)
+			return err
+		}, nil
+	}}
+}
+
+// RemoveTrucks removes several trucks under one lock acquisition
+func (tm *truckManager) RemoveTrucks(ids []string, opts ...BatchOption) (BatchResult, error) {
+	items := make([]batchItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, tm.removeItem(id))
+	}
+	return tm.applyBatch("remove", items, opts)
+}
+
+// removeItem is a batch item removing the truck with the given ID
+func (tm *truckManager) removeItem(id string) batchItem {
+	return batchItem{id: id, op: "remove", apply: func() (func() error, error) {
+		if id == "" {
+			return nil, ErrEmptyID
+		}
+		windows := tm.maintenance[id]
+		fuel := tm.fuel[id]
+		reservation, reserved := tm.reservations[id]
+		// Removal cancels these, so an undo has to book them again
+		booked := tm.bookedAppointmentsLocked(id)
+		// A truck removed once before may already have a recycle bin entry
+		prior := tm.recycled[id]
+		removed, err := tm.recycleLocked(id)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			if err := tm.addLocked(removed); err != nil {
+				return err
+			}
+			if err := tm.setRecycledLocked(id, prior); err != nil {
+				return err
+			}
+			if windows != nil {
+				tm.maintenance[id] = windows
+			}
+			if fuel != nil {
+				tm.fuel[id] = fuel
+			}
+			if reserved {
+				tm.reservations[id] = reservation
+			}
+			for _, a := range booked {
+				a.Status = AppointmentBooked
+			}
+			return nil
+		}, nil
+	}}
+}
+
+// UpdateCargoBatch sets the cargo capacity of several trucks under one lock
+// acquisition. Items are applied in ID order.
+func (tm *truckManager) UpdateCargoBatch(capacity map[string]int, opts ...BatchOption) (BatchResult, error) {
+	ids := make([]string, 0, len(capacity))
+	for id := range capacity {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	items := make([]batchItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, tm.updateItem(id, func(Truck) (UpdateSpec, error) {
+			return NewUpdateSpec().WithCapacity(capacity[id]), nil
+		}))
+	}
+	return tm.applyBatch("update", items, opts)
+}
+
+// updateItem is a batch item applying the spec that change returns for the
+// truck as it is when the item is applied. Undoing it restores the
+// capacity and load the truck had before.
+func (tm *truckManager) updateItem(id string, change func(Truck) (UpdateSpec, error)) batchItem {
+	return batchItem{id: id, op: "update", apply: func() (func() error, error) {
+		if id == "" {
+			return nil, ErrEmptyID
+		}
+		truck, exist := tm.truck(id)
+		if !exist {
+			return nil, ErrTruckNotFound
+		}
+		spec, err := change(*truck)
+		if err != nil {
+			return nil, err
+		}
+		if err := spec.validate(); err != nil {
+			return nil, err
+		}
+		previous := NewUpdateSpec().WithCapacity(truck.Capacity).WithCurrentLoad(truck.CurrentLoad)
+		if err := tm.updateLocked(id, spec); err != nil {
+			return nil, err
+		}
+		return func() error {
+			return tm.updateLocked(id, previous)
+		}, nil
+	}}
+}
+
+// applyBatch applies items in order under the write lock. In all-or-nothing
+// mode the first failure undoes every applied item in reverse order. op
+// names the batch in log records.
+func (tm *truckManager) applyBatch(op string, items []batchItem, opts []BatchOption) (BatchResult, error) {
+	var cfg batchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	// Hold events back until the batch is known to stick
+	committed := false
+	if cfg.allOrNothing {
+		var held []FleetEvent
+		tm.heldEvents = &held
+		defer func() {
+			tm.heldEvents = nil
+			if committed {
+				for _, ev := range held {
+					tm.events.publish(ev)
+				}
+			}
+		}()
+	}
+
+	var result BatchResult
+	var undos []func() error
+	// Changes are audited only once the batch is known to stick
+	var audited []AuditEntry
+	for _, item := range items {
+		before := tm.snapshotLocked(item.id)
+		undo, err := item.apply()
+		if err == nil {
+			result.Succeeded = append(result.Succeeded, item.id)
+			undos = append(undos, undo)
+			audited = append(audited, AuditEntry{Op: item.op, TruckID: item.id, Before: before, After: tm.snapshotLocked(item.id)})
+			continue
+		}
+
+		result.Failed = append(result.Failed, BatchItemError{ID: item.id, Err: err})
+		if !cfg.allOrNothing {
+			continue
+		}
+
+		// Roll back everything applied so far
+		aborted := BatchResult{Failed: result.Failed}
+		for i := len(undos) - 1; i >= 0; i-- {
+			if undoErr := undos[i](); undoErr != nil {
+				tm.recordBatch(op, aborted)
+				return aborted, fmt.Errorf("%w: %s: %w; rollback failed: %v", ErrBatchAborted, item.id, err, undoErr)
+			}
+		}
+		tm.recordBatch(op, aborted)
+		return aborted, fmt.Errorf("%w: %s: %w", ErrBatchAborted, item.id, err)
+	}
+
+	committed = true
+	for _, e := range audited {
+		tm.auditLocked(context.Background(), e.Op, e.TruckID, e.Before, e.After)
+	}
+	tm.recordBatch(op, result)
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("existing", 1)
+
+	result, err := manager.AddTrucks([]Truck{{ID: "1", Capacity: 100}, {ID: "existing", Capacity: 5}, {ID: "2", Capacity: -1}, {ID: "3", Capacity: 300}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Succeeded) != 2 || result.Succeeded[0] != "1" || result.Succeeded[1] != "3" {
+		t.Errorf("Expected 1 and 3 to succeed, got %v", result.Succeeded)
+	}
+	if len(result.Failed) != 2 || result.Failed[0].Err != ErrTruckExist || result.Failed[1].Err != ErrInvalidCargo {
+		t.Errorf("Expected duplicate and invalid cargo failures, got %v", result.Failed)
+	}
+	if len(manager.trucks) != 3 {
+		t.Errorf("Expected 3 trucks, got %d", len(manager.trucks))
+	}
+}
+
+func TestAddTrucksAllOrNothing(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("existing", 1)
+
+	result, err := manager.AddTrucks([]Truck{{ID: "1", Capacity: 100}, {ID: "existing", Capacity: 5}}, AllOrNothing())
+	if !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("Expected batch aborted error, got %v", err)
+	}
+	if len(result.Succeeded) != 0 || len(result.Failed) != 1 || result.Failed[0].ID != "existing" {
+		t.Errorf("Expected only the failing item to be reported, got %+v", result)
+	}
+	if _, err := manager.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck 1 to be rolled back, got %v", err)
+	}
+	if manager.totalCapacity.Load() != 1 {
+		t.Errorf("Expected total capacity to be restored to 1, got %d", manager.totalCapacity.Load())
+	}
+}
+
+func TestRemoveTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+
+	result, err := manager.RemoveTrucks([]string{"1", "missing"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Succeeded) != 1 || len(result.Failed) != 1 || result.Failed[0].Err != ErrTruckNotFound {
+		t.Errorf("Expected one removal and one not found, got %+v", result)
+	}
+
+	_, err = manager.RemoveTrucks([]string{"2", "missing"}, AllOrNothing())
+	if !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("Expected batch aborted error, got %v", err)
+	}
+	if truck, err := manager.GetTruck("2"); err != nil || truck.Capacity != 200 {
+		t.Errorf("Expected truck 2 to be restored, got %+v, %v", truck, err)
+	}
+}
+
+func TestUpdateCargoBatch(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+
+	result, err := manager.UpdateCargoBatch(map[string]int{"1": 150, "2": 250, "3": 350})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Succeeded) != 2 || len(result.Failed) != 1 || result.Failed[0].ID != "3" {
+		t.Errorf("Expected 3 to fail, got %+v", result)
+	}
+
+	_, err = manager.UpdateCargoBatch(map[string]int{"1": 1, "2": -1}, AllOrNothing())
+	if !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("Expected batch aborted error, got %v", err)
+	}
+	if truck, _ := manager.GetTruck("1"); truck.Capacity != 150 {
+		t.Errorf("Expected truck 1 capacity to be rolled back to 150, got %d", truck.Capacity)
+	}
+}
+
+func TestBatchRespectsLimits(t *testing.T) {
+	manager := NewFleetManager()
+	manager.SetLimits(FleetLimits{MaxTrucks: 2})
+
+	result, _ := manager.AddTrucks([]Truck{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+	if len(result.Failed) != 1 || !errors.Is(result.Failed[0].Err, ErrFleetLimitReached) {
+		t.Errorf("Expected the third truck to hit the fleet limit, got %+v", result.Failed)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrUnknownNamespace is returned for a namespace other than Blue or Green
+var ErrUnknownNamespace = errors.New("unknown namespace")
+
+// Namespace names one of the two fleets held by a BlueGreen
+type Namespace string
+
+// The two namespaces of a BlueGreen
+const (
+	Blue  Namespace = "blue"
+	Green Namespace = "green"
+)
+
+// other returns the namespace that isn't ns
+func (ns Namespace) other() Namespace {
+	if ns == Blue {
+		return Green
+	}
+	return Blue
+}
+
+// BlueGreenRouting decides where a BlueGreen sends calls. Reads and writes
+// can go to different namespaces, and with MirrorWrites every write is also
+// applied to the other namespace, so a candidate can be kept in step with
+// live traffic while a migration is rehearsed on it.
+type BlueGreenRouting struct {
+	Reads        Namespace
+	Writes       Namespace
+	MirrorWrites bool
+}
+
+// validate checks both namespaces are known
+func (r BlueGreenRouting) validate() error {
+	for _, ns := range []Namespace{r.Reads, r.Writes} {
+		if ns != Blue && ns != Green {
+			return fmt.Errorf("%w: %q", ErrUnknownNamespace, ns)
+		}
+	}
+	return nil
+}
+
+// BlueGreen holds two fleets, the current one and a candidate, and
+// implements FleetManager by routing each call to one of them. Switching
+// namespaces waits for calls in flight, so no call sees half of a switch.
+type BlueGreen struct {
+	// mu guards routing; calls hold it for reading until they return
+	mu      sync.RWMutex
+	fleets  map[Namespace]FleetManager
+	routing BlueGreenRouting
+	// mirrorMu serializes mirrored writes, so both namespaces apply them in the same order
+	mirrorMu sync.Mutex
+	mirrored atomic.Int64 // mirrored writes whose outcome differed on the other namespace
+}
+
+// NewBlueGreen creates a BlueGreen serving blue, with green as the candidate
+func NewBlueGreen(blue, green FleetManager) *BlueGreen {
+	return &BlueGreen{
+		fleets:  map[Namespace]FleetManager{Blue: blue, Green: green},
+		routing: BlueGreenRouting{Reads: Blue, Writes: Blue},
+	}
+}
+
+// Namespace returns the fleet held in ns, for preparing or checking it
+// directly. Calls made on it bypass the routing.
+func (bg *BlueGreen) Namespace(ns Namespace) (FleetManager, error) {
+	if ns != Blue && ns != Green {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownNamespace, ns)
+	}
+	return bg.fleets[ns], nil
+}
+
+// Routing returns the current routing
+func (bg *BlueGreen) Routing() BlueGreenRouting {
+	bg.mu.RLock()
+	defer bg.mu.RUnlock()
+	return bg.routing
+}
+
+// SetRouting changes where calls go
+func (bg *BlueGreen) SetRouting(r BlueGreenRouting) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	bg.routing = r
+	return nil
+}
+
+// Switch makes the namespace currently taking writes the candidate and
+// moves reads and writes to the other one, keeping MirrorWrites as it was.
+// It returns the namespace now live. Switching again rolls back.
+func (bg *BlueGreen) Switch() Namespace {
+	bg.mu.Lock()
+	defer bg.mu.Unlock()
+	live := bg.routing.Writes.other()
+	bg.routing.Reads, bg.routing.Writes = live, live
+	return live
+}
+
+// MirrorFailures returns how many mirrored writes succeeded on one
+// namespace and failed on the other. A candidate in step with the live
+// fleet has none.
+func (bg *BlueGreen) MirrorFailures() int {
+	return int(bg.mirrored.Load())
+}
+
+// read runs call against the namespace taking reads
+func (bg *BlueGreen) read(call func(FleetManager)) {
+	bg.mu.RLock()
+	defer bg.mu.RUnlock()
+	call(bg.fleets[bg.routing.Reads])
+}
+
+// write runs call against the namespace taking writes, and against the
+// other namespace too when writes are mirrored. The caller sees the result
+// from the namespace taking writes.
+func (bg *BlueGreen) write(call func(FleetManager) error) error {
+	bg.mu.RLock()
+	defer bg.mu.RUnlock()
+
+	if !bg.routing.MirrorWrites {
+		return call(bg.fleets[bg.routing.Writes])
+	}
+	bg.mirrorMu.Lock()
+	defer bg.mirrorMu.Unlock()
+	err := call(bg.fleets[bg.routing.Writes])
+	if mirrorErr := call(bg.fleets[bg.routing.Writes.other()]); (mirrorErr == nil) != (err == nil) {
+		bg.mirrored.Add(1)
+	}
+	return err
+}
+
+// AddTruck adds the truck to the namespace taking writes
+func (bg *BlueGreen) AddTruck(id string, capacity int) error {
+	return bg.write(func(m FleetManager) error { return m.AddTruck(id, capacity) })
+}
+
+// GetTruck reads the truck from the namespace taking reads
+func (bg *BlueGreen) GetTruck(id string) (truck Truck, err error) {
+	bg.read(func(m FleetManager) { truck, err = m.GetTruck(id) })
+	return truck, err
+}
+
+// RemoveTruck removes the truck from the namespace taking writes
+func (bg *BlueGreen) RemoveTruck(id string) error {
+	return bg.write(func(m FleetManager) error { return m.RemoveTruck(id) })
+}
+
+// UpdateTruckCargo updates the truck in the namespace taking writes
+func (bg *BlueGreen) UpdateTruckCargo(id string, capacity int) error {
+	return bg.write(func(m FleetManager) error { return m.UpdateTruckCargo(id, capacity) })
+}
+
+// LoadCargo loads the truck in the namespace taking writes
+func (bg *BlueGreen) LoadCargo(id string, amount int) error {
+	return bg.write(func(m FleetManager) error { return m.LoadCargo(id, amount) })
+}
+
+// UnloadCargo unloads the truck in the namespace taking writes
+func (bg *BlueGreen) UnloadCargo(id string, amount int) error {
+	return bg.write(func(m FleetManager) error { return m.UnloadCargo(id, amount) })
+}
+
+// ListTrucks lists the namespace taking reads
+func (bg *BlueGreen) ListTrucks(offset, limit int) (trucks []Truck, err error) {
+	bg.read(func(m FleetManager) { trucks, err = m.ListTrucks(offset, limit) })
+	return trucks, err
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+var _ FleetManager = (*BlueGreen)(nil)
+
+// newTestBlueGreen returns a BlueGreen over two empty fleets
+func newTestBlueGreen() (*BlueGreen, *truckManager, *truckManager) {
+	blue := NewFleetManager()
+	green := NewFleetManager()
+	return NewBlueGreen(blue, green), blue, green
+}
+
+func TestBlueGreenRoutesToBlue(t *testing.T) {
+	bg, blue, green := newTestBlueGreen()
+	bg.AddTruck("1", 100)
+
+	if _, err := blue.GetTruck("1"); err != nil {
+		t.Errorf("Expected the truck in blue, got %v", err)
+	}
+	if _, err := green.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected green to be untouched, got %v", err)
+	}
+	if truck, err := bg.GetTruck("1"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected to read the truck back, got %+v and %v", truck, err)
+	}
+}
+
+func TestBlueGreenSwitch(t *testing.T) {
+	bg, blue, green := newTestBlueGreen()
+	blue.AddTruck("1", 100)
+	// Rehearse a migration on the candidate
+	green.AddTruck("1", 200)
+
+	if live := bg.Switch(); live != Green {
+		t.Errorf("Expected green to be live, got %s", live)
+	}
+	truck, _ := bg.GetTruck("1")
+	if truck.Capacity != 200 {
+		t.Errorf("Expected green's capacity 200, got %d", truck.Capacity)
+	}
+	bg.LoadCargo("1", 10)
+	if truck, _ := blue.GetTruck("1"); truck.CurrentLoad != 0 {
+		t.Errorf("Expected blue to be untouched after the switch, got %+v", truck)
+	}
+
+	// Switching again rolls back
+	if live := bg.Switch(); live != Blue {
+		t.Errorf("Expected blue to be live again, got %s", live)
+	}
+	if truck, _ := bg.GetTruck("1"); truck.Capacity != 100 {
+		t.Errorf("Expected blue's capacity 100, got %d", truck.Capacity)
+	}
+}
+
+func TestBlueGreenSplitRouting(t *testing.T) {
+	bg, blue, _ := newTestBlueGreen()
+	if err := bg.SetRouting(BlueGreenRouting{Reads: Green, Writes: Blue}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	bg.AddTruck("1", 100)
+
+	if _, err := bg.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected reads from green to miss the truck, got %v", err)
+	}
+	if _, err := blue.GetTruck("1"); err != nil {
+		t.Errorf("Expected the write in blue, got %v", err)
+	}
+	if err := bg.SetRouting(BlueGreenRouting{Reads: "red", Writes: Blue}); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("Expected ErrUnknownNamespace, got %v", err)
+	}
+	if _, err := bg.Namespace("red"); !errors.Is(err, ErrUnknownNamespace) {
+		t.Errorf("Expected ErrUnknownNamespace, got %v", err)
+	}
+}
+
+func TestBlueGreenMirrorWrites(t *testing.T) {
+	bg, _, green := newTestBlueGreen()
+	bg.SetRouting(BlueGreenRouting{Reads: Blue, Writes: Blue, MirrorWrites: true})
+
+	bg.AddTruck("1", 100)
+	bg.LoadCargo("1", 40)
+	if truck, _ := green.GetTruck("1"); truck.CurrentLoad != 40 {
+		t.Errorf("Expected the writes mirrored to green, got %+v", truck)
+	}
+	if bg.MirrorFailures() != 0 {
+		t.Errorf("Expected no mirror failures, got %d", bg.MirrorFailures())
+	}
+
+	// The candidate drifts: a write that succeeds on blue fails on green
+	green.RemoveTruck("1")
+	bg.UnloadCargo("1", 10)
+	if bg.MirrorFailures() != 1 {
+		t.Errorf("Expected 1 mirror failure, got %d", bg.MirrorFailures())
+	}
+
+	if routing := bg.Routing(); !routing.MirrorWrites {
+		t.Errorf("Expected mirroring to stay on, got %+v", routing)
+	}
+}
+
+func TestBlueGreenConcurrentSwitch(t *testing.T) {
+	bg, blue, green := newTestBlueGreen()
+	blue.AddTruck("1", 1000000)
+	green.AddTruck("1", 1000000)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if err := bg.LoadCargo("1", 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		bg.Switch()
+	}
+	wg.Wait()
+
+	b, _ := blue.GetTruck("1")
+	g, _ := green.GetTruck("1")
+	if b.CurrentLoad+g.CurrentLoad != 8*500 {
+		t.Errorf("Expected every load applied exactly once, got %d and %d", b.CurrentLoad, g.CurrentLoad)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets inside a BoltStorage file
+var (
+	boltTrucksBucket  = []byte("trucks")
+	boltRemovedBucket = []byte("removed")
+)
+
+// BoltStorage is a Storage backed by an embedded BoltDB file. Each change is
+// its own transaction, synced before it returns, and only the changed truck
+// is written, so large fleets don't pay for rewriting the whole file.
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage opens the BoltDB file at path, creating it if needed. The
+// file is locked while open, so a second process opening it fails after a
+// second instead of waiting forever.
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltTrucksBucket, boltRemovedBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStorage{db: db}, nil
+}
+
+// Save stores or replaces the truck
+func (s *BoltStorage) Save(t Truck) error {
+	return s.put(boltTrucksBucket, t.ID, t)
+}
+
+// Load returns the stored truck with the given ID
+func (s *BoltStorage) Load(id string) (Truck, error) {
+	var t Truck
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltTrucksBucket).Get([]byte(id))
+		if data == nil {
+			return ErrTruckNotFound
+		}
+		return json.Unmarshal(data, &t)
+	})
+	return t, err
+}
+
+// Delete removes the stored truck with the given ID
+func (s *BoltStorage) Delete(id string) error {
+	return s.delete(boltTrucksBucket, id, ErrTruckNotFound)
+}
+
+// List returns all stored trucks ordered by ID
+func (s *BoltStorage) List() ([]Truck, error) {
+	var trucks []Truck
+	err := s.db.View(func(tx *bolt.Tx) error {
+		// Keys iterate in byte order, which is ID order
+		return tx.Bucket(boltTrucksBucket).ForEach(func(_, data []byte) error {
+			var t Truck
+			if err := json.Unmarshal(data, &t); err != nil {
+				return err
+			}
+			trucks = append(trucks, t)
+			return nil
+		})
+	})
+	return trucks, err
+}
+
+// SaveRemoved stores or replaces a recycle bin entry
+func (s *BoltStorage) SaveRemoved(r RemovedTruck) error {
+	return s.put(boltRemovedBucket, r.Truck.ID, r)
+}
+
+// DeleteRemoved removes the recycle bin entry of the truck with the given ID
+func (s *BoltStorage) DeleteRemoved(id string) error {
+	return s.delete(boltRemovedBucket, id, ErrTruckNotRemoved)
+}
+
+// ListRemoved returns the recycle bin entries ordered by truck ID
+func (s *BoltStorage) ListRemoved() ([]RemovedTruck, error) {
+	var removed []RemovedTruck
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltRemovedBucket).ForEach(func(_, data []byte) error {
+			var r RemovedTruck
+			if err := json.Unmarshal(data, &r); err != nil {
+				return err
+			}
+			removed = append(removed, r)
+			return nil
+		})
+	})
+	return removed, err
+}
+
+// Close closes the database file and releases its lock
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
+
+// put writes v as JSON under key in bucket
+func (s *BoltStorage) put(bucket []byte, key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+}
+
+// delete removes key from bucket, returning notFound if it isn't there
+func (s *BoltStorage) delete(bucket []byte, key string, notFound error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if b.Get([]byte(key)) == nil {
+			return notFound
+		}
+		return b.Delete([]byte(key))
+	})
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltStorageSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.db")
+	storage, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	manager, _ := OpenTruckManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.LoadCargo("2", 50)
+	manager.RemoveTruck("1")
+	storage.Close()
+
+	storage, err = NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer storage.Close()
+	restarted, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if restarted.Exists("1") {
+		t.Errorf("Expected truck 1 to stay removed")
+	}
+	truck, err := restarted.GetTruck("2")
+	if err != nil || truck.CurrentLoad != 50 {
+		t.Errorf("Expected truck 2 with load 50, got %+v, %v", truck, err)
+	}
+	if err := restarted.RestoreTruck("1"); err != nil {
+		t.Errorf("Expected truck 1 restorable after restart, got %v", err)
+	}
+}
+
+func TestBoltStorage(t *testing.T) {
+	storage, err := NewBoltStorage(filepath.Join(t.TempDir(), "fleet.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer storage.Close()
+
+	storage.Save(Truck{ID: "b", Capacity: 200})
+	storage.Save(Truck{ID: "a", Capacity: 100})
+	if truck, err := storage.Load("a"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck a with capacity 100, got %+v, %v", truck, err)
+	}
+	trucks, _ := storage.List()
+	if len(trucks) != 2 || trucks[0].ID != "a" || trucks[1].ID != "b" {
+		t.Errorf("Expected trucks ordered by ID, got %+v", trucks)
+	}
+	if _, err := storage.Load("missing"); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := storage.Delete("missing"); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := storage.DeleteRemoved("missing"); !errors.Is(err, ErrTruckNotRemoved) {
+		t.Errorf("Expected not removed error, got %v", err)
+	}
+}
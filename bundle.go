@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Errors for signed export bundles
+var (
+	ErrInvalidBundle  = errors.New("invalid bundle")
+	ErrBadSignature   = errors.New("bundle signature does not match")
+	ErrBundleTampered = errors.New("bundle contents do not match manifest")
+)
+
+// Files every bundle holds besides its contents
+const (
+	bundleManifest  = "manifest.json"
+	bundleSignature = "manifest.sig"
+)
+
+// BundleManifest lists the files of a bundle with their sizes and SHA-256
+// hashes. The manifest is what gets signed, so it vouches for every file.
+type BundleManifest struct {
+	Created time.Time    `json:"created"`
+	Files   []BundleFile `json:"files"`
+}
+
+// BundleFile is one entry of a BundleManifest
+type BundleFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// WriteBundle writes files into dir, which is created if needed, together
+// with a manifest of their hashes and a detached ed25519 signature of the
+// manifest. File names must be plain names without directories.
+func WriteBundle(dir string, files map[string][]byte, key ed25519.PrivateKey) (BundleManifest, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return BundleManifest{}, fmt.Errorf("%w: private key must be %d bytes", ErrInvalidBundle, ed25519.PrivateKeySize)
+	}
+	manifest := BundleManifest{Created: time.Now().UTC()}
+	for name, data := range files {
+		if err := checkBundleName(name); err != nil {
+			return BundleManifest{}, err
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, BundleFile{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Name < manifest.Files[j].Name })
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n"
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return BundleManifest{}, err
+	}
+	// Contents go first, so a crash never leaves a signed manifest for missing files
+	for _, f := range manifest.Files {
+		if err := writeFileAtomic(filepath.Join(dir, f.Name), files[f.Name]); err != nil {
+			return BundleManifest{}, err
+		}
+	}
+	if err := writeFileAtomic(filepath.Join(dir, bundleManifest), data); err != nil {
+		return BundleManifest{}, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, bundleSignature), []byte(signature)); err != nil {
+		return BundleManifest{}, err
+	}
+	return manifest, nil
+}
+
+// checkBundleName rejects names that would escape the bundle or clash with its manifest
+func checkBundleName(name string) error {
+	if name == "" || name == "." || name == ".." || name == bundleManifest || name == bundleSignature || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: file name %q", ErrInvalidBundle, name)
+	}
+	return nil
+}
+
+// VerifyBundle checks that the manifest in dir was signed with the private
+// half of key, that every listed file has the recorded size and hash, and
+// that the directory holds no files the manifest doesn't list
+func VerifyBundle(dir string, key ed25519.PublicKey) (BundleManifest, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return BundleManifest{}, fmt.Errorf("%w: public key must be %d bytes", ErrInvalidBundle, ed25519.PublicKeySize)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifest))
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	encoded, err := os.ReadFile(filepath.Join(dir, bundleSignature))
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return BundleManifest{}, fmt.Errorf("%w: reading signature: %v", ErrInvalidBundle, err)
+	}
+	if !ed25519.Verify(key, data, signature) {
+		return BundleManifest{}, ErrBadSignature
+	}
+
+	var manifest BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return BundleManifest{}, fmt.Errorf("%w: reading manifest: %v", ErrInvalidBundle, err)
+	}
+	listed := map[string]bool{bundleManifest: true, bundleSignature: true}
+	for _, f := range manifest.Files {
+		if err := checkBundleName(f.Name); err != nil {
+			return BundleManifest{}, err
+		}
+		listed[f.Name] = true
+		content, err := os.ReadFile(filepath.Join(dir, f.Name))
+		if err != nil {
+			return BundleManifest{}, fmt.Errorf("%w: %s: %v", ErrBundleTampered, f.Name, err)
+		}
+		sum := sha256.Sum256(content)
+		if int64(len(content)) != f.Size || hex.EncodeToString(sum[:]) != f.SHA256 {
+			return BundleManifest{}, fmt.Errorf("%w: %s has changed", ErrBundleTampered, f.Name)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	for _, entry := range entries {
+		if !listed[entry.Name()] {
+			return BundleManifest{}, fmt.Errorf("%w: %s is not in the manifest", ErrBundleTampered, entry.Name())
+		}
+	}
+	return manifest, nil
+}
+
+// ExportBundle exports the fleet in each format, JSON if none are given,
+// as a signed bundle in dir. The fleet files are named fleet.json and
+// fleet.csv, and the fuel report over all recorded time is fuel.json.
+func (tm *truckManager) ExportBundle(dir string, key ed25519.PrivateKey, formats ...Format) (BundleManifest, error) {
+	if len(formats) == 0 {
+		formats = []Format{FormatJSON}
+	}
+	files := make(map[string][]byte, len(formats)+1)
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := tm.ExportFleet(&buf, format); err != nil {
+			return BundleManifest{}, err
+		}
+		files["fleet."+string(format)] = buf.Bytes()
+	}
+	report, err := json.MarshalIndent(tm.FuelReport(time.Time{}, time.Now().Add(time.Nanosecond)), "", "  ")
+	if err != nil {
+		return BundleManifest{}, err
+	}
+	files["fuel.json"] = report
+	return WriteBundle(dir, files, key)
+}
+
+// readPublicKey reads a hex-encoded ed25519 public key from path
+func readPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s: expected a hex-encoded %d-byte ed25519 public key", path, ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// runVerifyCommand checks a signed bundle against a public key
+func runVerifyCommand(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	keyPath := fs.String("key", "", "file holding the signer's hex-encoded ed25519 public key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keyPath == "" || fs.NArg() != 1 {
+		return fmt.Errorf("usage: verify -key <public key file> <bundle dir>")
+	}
+
+	key, err := readPublicKey(*keyPath)
+	if err != nil {
+		return err
+	}
+	manifest, err := VerifyBundle(fs.Arg(0), key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle OK: %d files signed %s\n", len(manifest.Files), manifest.Created.Format(time.RFC3339))
+	for _, f := range manifest.Files {
+		fmt.Printf("  %s  %d bytes  sha256:%s\n", f.Name, f.Size, f.SHA256)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newBundleKey returns a fresh signing key pair
+func newBundleKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("Expected no error generating a key, got %v", err)
+	}
+	return public, private
+}
+
+func TestExportBundleVerifies(t *testing.T) {
+	public, private := newBundleKey(t)
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	written, err := manager.ExportBundle(dir, private, FormatJSON, FormatCSV)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(written.Files) != 3 || written.Files[0].Name != "fleet.csv" || written.Files[1].Name != "fleet.json" || written.Files[2].Name != "fuel.json" {
+		t.Errorf("Expected fleet.csv, fleet.json and fuel.json, got %+v", written.Files)
+	}
+
+	verified, err := VerifyBundle(dir, public)
+	if err != nil {
+		t.Fatalf("Expected bundle to verify, got %v", err)
+	}
+	if len(verified.Files) != 3 || !verified.Created.Equal(written.Created) {
+		t.Errorf("Expected the written manifest back, got %+v", verified)
+	}
+}
+
+func TestVerifyBundleDetectsTampering(t *testing.T) {
+	public, private := newBundleKey(t)
+	files := map[string][]byte{"fleet.json": []byte(`{"trucks": []}`)}
+
+	cases := []struct {
+		name   string
+		tamper func(dir string)
+		want   error
+	}{
+		{"changed file", func(dir string) {
+			os.WriteFile(filepath.Join(dir, "fleet.json"), []byte(`{"trucks": [{}]}`), 0o644)
+		}, ErrBundleTampered},
+		{"missing file", func(dir string) {
+			os.Remove(filepath.Join(dir, "fleet.json"))
+		}, ErrBundleTampered},
+		{"extra file", func(dir string) {
+			os.WriteFile(filepath.Join(dir, "extra.csv"), []byte("id\n"), 0o644)
+		}, ErrBundleTampered},
+		{"changed manifest", func(dir string) {
+			data, _ := os.ReadFile(filepath.Join(dir, bundleManifest))
+			os.WriteFile(filepath.Join(dir, bundleManifest), append(data, ' '), 0o644)
+		}, ErrBadSignature},
+		{"missing signature", func(dir string) {
+			os.Remove(filepath.Join(dir, bundleSignature))
+		}, ErrInvalidBundle},
+	}
+	for _, c := range cases {
+		dir := t.TempDir()
+		if _, err := WriteBundle(dir, files, private); err != nil {
+			t.Fatalf("%s: Expected no error, got %v", c.name, err)
+		}
+		c.tamper(dir)
+		if _, err := VerifyBundle(dir, public); !errors.Is(err, c.want) {
+			t.Errorf("%s: Expected %v, got %v", c.name, c.want, err)
+		}
+	}
+}
+
+func TestVerifyBundleWrongKey(t *testing.T) {
+	_, private := newBundleKey(t)
+	other, _ := newBundleKey(t)
+	dir := t.TempDir()
+	WriteBundle(dir, map[string][]byte{"report.txt": []byte("ok")}, private)
+
+	if _, err := VerifyBundle(dir, other); err != ErrBadSignature {
+		t.Errorf("Expected ErrBadSignature, got %v", err)
+	}
+}
+
+func TestWriteBundleRejectsBadNames(t *testing.T) {
+	_, private := newBundleKey(t)
+	for _, name := range []string{"", "../escape", "sub/file", bundleManifest, bundleSignature} {
+		if _, err := WriteBundle(t.TempDir(), map[string][]byte{name: nil}, private); !errors.Is(err, ErrInvalidBundle) {
+			t.Errorf("Expected ErrInvalidBundle for %q, got %v", name, err)
+		}
+	}
+}
+
+func TestVerifyCommand(t *testing.T) {
+	public, private := newBundleKey(t)
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "bundle")
+	WriteBundle(bundle, map[string][]byte{"report.txt": []byte("ok")}, private)
+	keyPath := filepath.Join(dir, "signer.pub")
+	os.WriteFile(keyPath, []byte(hex.EncodeToString(public)+"\n"), 0o644)
+
+	if err := runVerifyCommand([]string{"-key", keyPath, bundle}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := runVerifyCommand([]string{bundle}); err == nil {
+		t.Errorf("Expected a usage error without -key")
+	}
+
+	os.WriteFile(filepath.Join(bundle, "report.txt"), []byte("changed"), 0o644)
+	if err := runVerifyCommand([]string{"-key", keyPath, bundle}); !errors.Is(err, ErrBundleTampered) {
+		t.Errorf("Expected ErrBundleTampered, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commands maps subcommand names to their entry points. Running the binary
+// without a subcommand runs the demo in main.
+var commands = map[string]func(args []string) error{
+	"anonymize": runAnonymizeCommand,
+	"diff":      runDiffCommand,
+	"load":      runLoadCommand,
+	"replay":    runReplayCommand,
+	"serve":     runServeCommand,
+	"verify":    runVerifyCommand,
+}
+
+// runCommand dispatches to the named subcommand
+func runCommand(name string, args []string) error {
+	run, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return run(args)
+}
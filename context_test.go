@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Check that the manager satisfies the context-aware interface
+var _ ContextFleetManager = (*truckManager)(nil)
+
+func TestContextCanceled(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := manager.AddTruckContext(ctx, "2", 200); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if _, err := manager.GetTruckContext(ctx, "1"); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if err := manager.UpdateTruckCargoContext(ctx, "1", 300); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if err := manager.RemoveTruckContext(ctx, "1"); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+	if _, err := manager.ListTrucksContext(ctx, 0, 10); err != context.Canceled {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+
+	// Nothing was applied
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 100 || manager.Exists("2") {
+		t.Errorf("Expected fleet to be unchanged, got %+v", manager.trucks)
+	}
+}
+
+func TestContextDeadlineWhileWaitingForLock(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	// Hold the lock past the caller's deadline
+	manager.Lock()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		manager.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := manager.UpdateTruckCargoContext(ctx, "1", 200)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 100 {
+		t.Errorf("Expected truck capacity to stay 100, got %d", truck.Capacity)
+	}
+}
+
+func TestContextSuccess(t *testing.T) {
+	manager := NewFleetManager()
+	ctx := context.Background()
+
+	if err := manager.AddTruckContext(ctx, "1", 100); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, err := manager.GetTruckContext(ctx, "1")
+	if err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck with capacity 100, got %+v, %v", truck, err)
+	}
+}
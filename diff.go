@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FleetDiff is the difference between two fleet snapshots. Trucks are
+// matched by ID and every list is ordered by ID.
+type FleetDiff struct {
+	Added   []Truck       `json:"added,omitempty"`   // in the second snapshot only
+	Removed []Truck       `json:"removed,omitempty"` // in the first snapshot only
+	Changed []TruckChange `json:"changed,omitempty"` // in both, with different fields
+}
+
+// TruckChange lists the fields of one truck that differ between snapshots
+type TruckChange struct {
+	ID     string        `json:"id"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange is one differing field. Fields are named as in a CSV export,
+// with tags as tag.<key>; a field missing from a snapshot is empty, and a
+// tag without a value reads as bareTag.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// bareTag stands for a tag without a value in a FieldChange
+const bareTag = "(set)"
+
+// IsEmpty reports whether the snapshots hold the same trucks
+func (d FleetDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two fleet snapshots, such as the trucks of two exports or
+// a fleet and its backup. Fields named in ignore, such as "version", are
+// not compared.
+func Diff(a, b []Truck, ignore ...string) FleetDiff {
+	skip := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		skip[field] = true
+	}
+	before := make(map[string]Truck, len(a))
+	for _, t := range a {
+		before[t.ID] = t
+	}
+	after := make(map[string]Truck, len(b))
+	for _, t := range b {
+		after[t.ID] = t
+	}
+
+	var d FleetDiff
+	for id, t := range after {
+		old, exist := before[id]
+		if !exist {
+			d.Added = append(d.Added, t.clone())
+			continue
+		}
+		if fields := diffFields(truckFields(old), truckFields(t), skip); len(fields) > 0 {
+			d.Changed = append(d.Changed, TruckChange{ID: id, Fields: fields})
+		}
+	}
+	for id, t := range before {
+		if _, exist := after[id]; !exist {
+			d.Removed = append(d.Removed, t.clone())
+		}
+	}
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].ID < d.Added[j].ID })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].ID < d.Removed[j].ID })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].ID < d.Changed[j].ID })
+	return d
+}
+
+// truckFields flattens a truck into named text fields for comparison
+func truckFields(t Truck) map[string]string {
+	fields := map[string]string{
+		"capacity":     strconv.Itoa(t.Capacity),
+		"current_load": strconv.Itoa(t.CurrentLoad),
+		"driver_id":    t.DriverID,
+		"route_id":     t.RouteID,
+		"yard_id":      t.YardID,
+		"version":      strconv.FormatUint(t.Version, 10),
+	}
+	if t.FuelCapacity != 0 || t.FuelLevel != 0 {
+		fields["fuel_capacity"] = strconv.FormatFloat(t.FuelCapacity, 'f', -1, 64)
+		fields["fuel_level"] = strconv.FormatFloat(t.FuelLevel, 'f', -1, 64)
+	}
+	if t.Location != nil {
+		fields["location"] = fmt.Sprintf("%g,%g@%s", t.Location.Lat, t.Location.Lon, t.Location.Time.Format(time.RFC3339Nano))
+	}
+	for name, value := range t.Custom {
+		fields[csvCustomPrefix+name] = value
+	}
+	for key, value := range t.Tags {
+		// A bare tag still differs from a missing one
+		if value == "" {
+			value = bareTag
+		}
+		fields["tag."+key] = value
+	}
+	return fields
+}
+
+// diffFields returns the fields that differ, ordered by name
+func diffFields(old, cur map[string]string, skip map[string]bool) []FieldChange {
+	names := make(map[string]bool, len(cur))
+	for name := range old {
+		names[name] = true
+	}
+	for name := range cur {
+		names[name] = true
+	}
+
+	var changes []FieldChange
+	for name := range names {
+		if skip[name] || old[name] == cur[name] {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Old: old[name], New: cur[name]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// WriteText writes the diff in a line-per-change form meant for people:
+// + for an added truck, - for a removed one and ~ for each changed field
+func (d FleetDiff) WriteText(w io.Writer) error {
+	for _, t := range d.Added {
+		if _, err := fmt.Fprintf(w, "+ %s capacity=%d load=%d\n", t.ID, t.Capacity, t.CurrentLoad); err != nil {
+			return err
+		}
+	}
+	for _, t := range d.Removed {
+		if _, err := fmt.Fprintf(w, "- %s capacity=%d load=%d\n", t.ID, t.Capacity, t.CurrentLoad); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Changed {
+		for _, f := range c.Fields {
+			if _, err := fmt.Fprintf(w, "~ %s %s: %q -> %q\n", c.ID, f.Field, f.Old, f.New); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readSnapshot reads the trucks of an export or fleet file. Files ending in
+// .csv are read as CSV, anything else as JSON.
+func readSnapshot(path string) ([]Truck, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []importRow
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		rows, err = readCSVRows(f)
+	} else {
+		rows, err = readJSONRows(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	trucks := make([]Truck, 0, len(rows))
+	for i, row := range rows {
+		if row.err != nil {
+			return nil, fmt.Errorf("%s: row %d: %w", path, i+1, row.err)
+		}
+		trucks = append(trucks, row.truck)
+	}
+	return trucks, nil
+}
+
+// runDiffCommand compares two fleet snapshots and exits non-zero if they differ
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	ignore := fs.String("ignore", "", "comma-separated fields not to compare, e.g. version,location")
+	asJSON := fs.Bool("json", false, "write the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: diff [-ignore fields] [-json] <before> <after>")
+	}
+
+	a, err := readSnapshot(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	b, err := readSnapshot(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	var skip []string
+	if *ignore != "" {
+		skip = strings.Split(*ignore, ",")
+	}
+	d := Diff(a, b, skip...)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	} else {
+		err = d.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if !d.IsEmpty() {
+		return fmt.Errorf("snapshots differ: %d added, %d removed, %d changed", len(d.Added), len(d.Removed), len(d.Changed))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	a := []Truck{
+		{ID: "1", Capacity: 100, Version: 1},
+		{ID: "2", Capacity: 200, CurrentLoad: 20, Version: 3, Tags: map[string]string{"region": "west"}},
+		{ID: "3", Capacity: 300, Version: 1},
+	}
+	b := []Truck{
+		{ID: "4", Capacity: 400, Version: 1},
+		{ID: "2", Capacity: 250, CurrentLoad: 20, Version: 4, Tags: map[string]string{"refrigerated": ""}},
+		{ID: "1", Capacity: 100, Version: 1},
+	}
+
+	d := Diff(a, b)
+	if len(d.Added) != 1 || d.Added[0].ID != "4" {
+		t.Errorf("Expected truck 4 added, got %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ID != "3" {
+		t.Errorf("Expected truck 3 removed, got %+v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].ID != "2" {
+		t.Fatalf("Expected truck 2 changed, got %+v", d.Changed)
+	}
+	expected := []FieldChange{
+		{Field: "capacity", Old: "200", New: "250"},
+		{Field: "tag.refrigerated", Old: "", New: bareTag},
+		{Field: "tag.region", Old: "west", New: ""},
+		{Field: "version", Old: "3", New: "4"},
+	}
+	fields := d.Changed[0].Fields
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %d changed fields, got %+v", len(expected), fields)
+	}
+	for i, f := range fields {
+		if f != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], f)
+		}
+	}
+}
+
+func TestDiffIgnore(t *testing.T) {
+	a := []Truck{{ID: "1", Capacity: 100, Version: 1, Location: &Location{Lat: 1, Lon: 2, Time: time.Unix(0, 0)}}}
+	b := []Truck{{ID: "1", Capacity: 100, Version: 7, Location: &Location{Lat: 3, Lon: 4, Time: time.Unix(60, 0)}}}
+
+	if d := Diff(a, b, "version", "location"); !d.IsEmpty() {
+		t.Errorf("Expected no difference, got %+v", d)
+	}
+	if d := Diff(a, b, "version"); len(d.Changed) != 1 || d.Changed[0].Fields[0].Field != "location" {
+		t.Errorf("Expected only the location to differ, got %+v", d)
+	}
+}
+
+func TestDiffWriteText(t *testing.T) {
+	d := Diff([]Truck{{ID: "1", Capacity: 100}}, []Truck{{ID: "1", Capacity: 150}, {ID: "2", Capacity: 50}})
+	var buf bytes.Buffer
+	d.WriteText(&buf)
+	expected := "+ 2 capacity=50 load=0\n~ 1 capacity: \"100\" -> \"150\"\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestDiffCommand(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	before := filepath.Join(dir, "before.json")
+	var buf bytes.Buffer
+	manager.ExportFleet(&buf, FormatJSON)
+	os.WriteFile(before, buf.Bytes(), 0o644)
+
+	// A CSV export of the same fleet matches, apart from the version it doesn't carry
+	same := filepath.Join(dir, "same.csv")
+	buf.Reset()
+	manager.ExportFleet(&buf, FormatCSV)
+	os.WriteFile(same, buf.Bytes(), 0o644)
+	if err := runDiffCommand([]string{"-ignore", "version", before, same}); err != nil {
+		t.Errorf("Expected no difference, got %v", err)
+	}
+
+	manager.LoadCargo("1", 10)
+	after := filepath.Join(dir, "after.json")
+	buf.Reset()
+	manager.ExportFleet(&buf, FormatJSON)
+	os.WriteFile(after, buf.Bytes(), 0o644)
+	err := runDiffCommand([]string{"-json", before, after})
+	if err == nil || !strings.Contains(err.Error(), "1 changed") {
+		t.Errorf("Expected one changed truck, got %v", err)
+	}
+
+	if err := runDiffCommand([]string{before}); err == nil {
+		t.Errorf("Expected a usage error")
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"sort"
+)
+
+// Errors for driver management
+var (
+	ErrDriverNotFound   = errors.New("driver not found")
+	ErrDriverExist      = errors.New("driver already exist")
+	ErrDriverAssigned   = errors.New("driver already assigned to a truck")
+	ErrTruckHasDriver   = errors.New("truck already has a driver")
+	ErrNoDriverAssigned = errors.New("truck has no driver assigned")
+)
+
+// DriverManager manages the drivers available to the fleet
+type DriverManager interface {
+	AddDriver(id string, name string) error
+	GetDriver(id string) (Driver, error)
+	RemoveDriver(id string) error
+	ListDrivers() []Driver
+}
+
+// Driver is a person who can be assigned to drive one truck at a time
+type Driver struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	TruckID string `json:"truck_id,omitempty"` // empty when the driver is unassigned
+	// Custom holds values for the custom fields defined with DefineField
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+// clone returns an independent copy of the driver
+func (d *Driver) clone() Driver {
+	c := *d
+	c.Custom = maps.Clone(d.Custom)
+	return c
+}
+
+// AddDriver registers a new, unassigned driver. Drivers are held in memory
+// only; the configured Storage does not persist them.
+func (tm *truckManager) AddDriver(id string, name string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.drivers[id]; exist {
+		return ErrDriverExist
+	}
+	tm.drivers[id] = &Driver{ID: id, Name: name}
+	return nil
+}
+
+// GetDriver retrieves a driver by ID
+func (tm *truckManager) GetDriver(id string) (Driver, error) {
+	if id == "" {
+		return Driver{}, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	driver, exist := tm.drivers[id]
+	if !exist {
+		return Driver{}, ErrDriverNotFound
+	}
+	return driver.clone(), nil
+}
+
+// RemoveDriver removes a driver, first releasing the truck they are assigned to
+func (tm *truckManager) RemoveDriver(id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	driver, exist := tm.drivers[id]
+	if !exist {
+		return ErrDriverNotFound
+	}
+	if driver.TruckID != "" {
+		if err := tm.setDriverLocked(driver.TruckID, ""); err != nil {
+			return err
+		}
+	}
+	delete(tm.drivers, id)
+	tm.dropInboxLocked(id)
+	return nil
+}
+
+// ListDrivers returns every driver ordered by ID
+func (tm *truckManager) ListDrivers() []Driver {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	drivers := make([]Driver, 0, len(tm.drivers))
+	for _, driver := range tm.drivers {
+		drivers = append(drivers, driver.clone())
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].ID < drivers[j].ID })
+	return drivers
+}
+
+// AssignDriver puts a driver in charge of a truck. A driver can drive only one
+// truck at a time and a truck has at most one driver, so both must be free.
+func (tm *truckManager) AssignDriver(truckID, driverID string) error {
+	if truckID == "" || driverID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	driver, exist := tm.drivers[driverID]
+	if !exist {
+		return ErrDriverNotFound
+	}
+	if driver.TruckID == truckID {
+		return nil
+	}
+	if driver.TruckID != "" {
+		return ErrDriverAssigned
+	}
+	if truck.DriverID != "" {
+		return ErrTruckHasDriver
+	}
+	return tm.setDriverLocked(truckID, driverID)
+}
+
+// UnassignDriver releases the driver assigned to a truck
+func (tm *truckManager) UnassignDriver(truckID string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if truck.DriverID == "" {
+		return ErrNoDriverAssigned
+	}
+	return tm.setDriverLocked(truckID, "")
+}
+
+// setDriverLocked changes the driver of an existing truck, keeping both sides
+// of the assignment in step. Callers must hold the write lock.
+func (tm *truckManager) setDriverLocked(truckID, driverID string) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.Version++
+	updated.DriverID = driverID
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+
+	if previous, exist := tm.drivers[truck.DriverID]; exist {
+		previous.TruckID = ""
+	}
+	if driver, exist := tm.drivers[driverID]; exist {
+		driver.TruckID = truckID
+	}
+	old := *truck
+	tm.trucks[truckID].store(&updated)
+	tm.emit(DriverChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("driver", truckID, &old, &updated)
+	return nil
+}
+
+// claimDriverLocked links a truck that is about to be added to the driver it
+// names. Since drivers are not persisted, a truck loaded from storage or
+// restored by a batch undo may name a driver that is unknown or busy; the
+// assignment is then dropped. Callers must hold the write lock.
+func (tm *truckManager) claimDriverLocked(truck *Truck) {
+	if truck.DriverID == "" {
+		return
+	}
+	driver, exist := tm.drivers[truck.DriverID]
+	if !exist || driver.TruckID != "" {
+		truck.DriverID = ""
+		return
+	}
+	driver.TruckID = truck.ID
+}
+
+// releaseDriverLocked frees the driver of a truck that is leaving the fleet.
+// Callers must hold the write lock.
+func (tm *truckManager) releaseDriverLocked(truck *Truck) {
+	if driver, exist := tm.drivers[truck.DriverID]; exist {
+		driver.TruckID = ""
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestAddDriver(t *testing.T) {
+	manager := NewFleetManager()
+
+	if err := manager.AddDriver("d1", "Amina"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.AddDriver("d1", "Amina"); err != ErrDriverExist {
+		t.Errorf("Expected driver exist error, got %v", err)
+	}
+	if err := manager.AddDriver("", "Nobody"); err != ErrEmptyID {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+
+	driver, err := manager.GetDriver("d1")
+	if err != nil || driver.Name != "Amina" || driver.TruckID != "" {
+		t.Errorf("Expected unassigned driver Amina, got %+v, %v", driver, err)
+	}
+}
+
+func TestListDrivers(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddDriver("d2", "Ben")
+	manager.AddDriver("d1", "Amina")
+
+	drivers := manager.ListDrivers()
+	if len(drivers) != 2 || drivers[0].ID != "d1" || drivers[1].ID != "d2" {
+		t.Errorf("Expected drivers d1 and d2 in order, got %+v", drivers)
+	}
+}
+
+func TestAssignDriver(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AddDriver("d2", "Ben")
+
+	if err := manager.AssignDriver("1", "d1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	driver, _ := manager.GetDriver("d1")
+	if truck.DriverID != "d1" || driver.TruckID != "1" {
+		t.Errorf("Expected truck 1 and driver d1 to be linked, got %+v and %+v", truck, driver)
+	}
+
+	if err := manager.AssignDriver("2", "d1"); err != ErrDriverAssigned {
+		t.Errorf("Expected driver assigned error, got %v", err)
+	}
+	if err := manager.AssignDriver("1", "d2"); err != ErrTruckHasDriver {
+		t.Errorf("Expected truck has driver error, got %v", err)
+	}
+	if err := manager.AssignDriver("3", "d2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := manager.AssignDriver("2", "d3"); err != ErrDriverNotFound {
+		t.Errorf("Expected driver not found error, got %v", err)
+	}
+}
+
+func TestUnassignDriver(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+
+	if err := manager.UnassignDriver("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.UnassignDriver("1"); err != ErrNoDriverAssigned {
+		t.Errorf("Expected no driver assigned error, got %v", err)
+	}
+	if err := manager.AssignDriver("2", "d1"); err != nil {
+		t.Errorf("Expected released driver to be assignable, got %v", err)
+	}
+}
+
+func TestRemoveTruckReleasesDriver(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+
+	manager.RemoveTruck("1")
+	driver, _ := manager.GetDriver("d1")
+	if driver.TruckID != "" {
+		t.Errorf("Expected driver to be released, got truck %s", driver.TruckID)
+	}
+}
+
+func TestRemoveDriverReleasesTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+
+	if err := manager.RemoveDriver("d1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.DriverID != "" {
+		t.Errorf("Expected truck to have no driver, got %s", truck.DriverID)
+	}
+	if err := manager.RemoveDriver("d1"); err != ErrDriverNotFound {
+		t.Errorf("Expected driver not found error, got %v", err)
+	}
+}
+
+func TestRemoveTrucksUndoRestoresDriver(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+
+	if _, err := manager.RemoveTrucks([]string{"1", "missing"}, AllOrNothing()); err == nil {
+		t.Fatalf("Expected the batch to abort")
+	}
+	truck, _ := manager.GetTruck("1")
+	driver, _ := manager.GetDriver("d1")
+	if truck.DriverID != "d1" || driver.TruckID != "1" {
+		t.Errorf("Expected the assignment to survive the rollback, got %+v and %+v", truck, driver)
+	}
+}
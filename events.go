@@ -0,0 +1,180 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType identifies the kind of change a FleetEvent describes
+type EventType string
+
+// Fleet change events
+const (
+	TruckAdded    EventType = "truck_added"
+	TruckRemoved  EventType = "truck_removed"
+	CargoUpdated  EventType = "cargo_updated"  // capacity or current load changed
+	DriverChanged EventType = "driver_changed" // driver assigned or released
+	RouteChanged  EventType = "route_changed"  // route assigned or released
+	YardChanged   EventType = "yard_changed"   // truck checked in to or out of a yard
+	FieldsChanged EventType = "fields_changed" // custom field set or cleared
+	TagsChanged   EventType = "tags_changed"   // tag set or removed
+	FuelChanged   EventType = "fuel_changed"   // tank size set, refuel or trip recorded
+	// LocationChanged is published for each accepted GPS position
+	LocationChanged EventType = "location_changed"
+	// YardOverCapacity is published when a truck is admitted to a yard with every slot taken
+	YardOverCapacity EventType = "yard_over_capacity"
+)
+
+// FleetEvent describes one change to the fleet. Old is nil for TruckAdded
+// and YardOverCapacity, and New is nil for TruckRemoved.
+type FleetEvent struct {
+	Type    EventType
+	TruckID string
+	Old     *Truck
+	New     *Truck
+	Time    time.Time
+}
+
+// Subscribe registers ch to receive every subsequent fleet event. Events are
+// queued per subscriber and delivered by a separate goroutine, so a slow
+// subscriber never blocks the manager; it only falls behind. Subscribing the
+// same channel twice has no effect.
+func (tm *truckManager) Subscribe(ch chan<- FleetEvent) {
+	tm.events.subscribe(ch)
+}
+
+// Unsubscribe stops delivery to ch and discards its undelivered events. The
+// channel is not closed, since it belongs to the caller.
+func (tm *truckManager) Unsubscribe(ch chan<- FleetEvent) {
+	tm.events.unsubscribe(ch)
+}
+
+// emit publishes an event, or holds it back while an atomic batch is in
+// progress so that rolled-back changes are never announced. Callers must
+// hold the write lock.
+func (tm *truckManager) emit(typ EventType, id string, before, after *Truck) {
+	// Subscribers get their own copies, so they can't reach the manager's state
+	if tm.heldEvents == nil && !tm.events.active() {
+		return
+	}
+	ev := FleetEvent{Type: typ, TruckID: id, Old: clonePtr(before), New: clonePtr(after), Time: time.Now()}
+	if tm.heldEvents != nil {
+		*tm.heldEvents = append(*tm.heldEvents, ev)
+		return
+	}
+	tm.events.publish(ev)
+}
+
+// clonePtr returns a pointer to a clone of t, or nil if t is nil
+func clonePtr(t *Truck) *Truck {
+	if t == nil {
+		return nil
+	}
+	c := t.clone()
+	return &c
+}
+
+// eventBus fans events out to subscribers. The zero value is ready to use.
+type eventBus struct {
+	mu   sync.RWMutex
+	subs map[chan<- FleetEvent]*subscriber
+	// count mirrors len(subs) so publishers can skip the bus when nobody listens
+	count atomic.Int32
+}
+
+// subscriber owns the queue and delivery goroutine for one channel
+type subscriber struct {
+	ch    chan<- FleetEvent
+	mu    sync.Mutex
+	queue []FleetEvent
+	wake  chan struct{}
+	done  chan struct{}
+}
+
+func (b *eventBus) subscribe(ch chan<- FleetEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = make(map[chan<- FleetEvent]*subscriber)
+	}
+	if _, exist := b.subs[ch]; exist {
+		return
+	}
+
+	s := &subscriber{
+		ch:   ch,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	b.subs[ch] = s
+	b.count.Add(1)
+	go s.run()
+}
+
+func (b *eventBus) unsubscribe(ch chan<- FleetEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, exist := b.subs[ch]; exist {
+		close(s.done)
+		delete(b.subs, ch)
+		b.count.Add(-1)
+	}
+}
+
+// active reports whether anyone is subscribed
+func (b *eventBus) active() bool {
+	return b.count.Load() > 0
+}
+
+func (b *eventBus) publish(ev FleetEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subs {
+		s.push(ev)
+	}
+}
+
+// push queues an event and wakes the delivery goroutine
+func (s *subscriber) push(ev FleetEvent) {
+	s.mu.Lock()
+	s.queue = append(s.queue, ev)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued events in order until the subscriber is removed
+func (s *subscriber) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		}
+
+		for {
+			s.mu.Lock()
+			if len(s.queue) == 0 {
+				s.queue = nil
+				s.mu.Unlock()
+				break
+			}
+			ev := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+
+			select {
+			case s.ch <- ev:
+			case <-s.done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// nextEvent waits briefly for an event on ch
+func nextEvent(t *testing.T, ch <-chan FleetEvent) FleetEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an event, got none")
+		return FleetEvent{}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	manager := NewFleetManager()
+	events := make(chan FleetEvent)
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	manager.AddTruck("1", 100)
+	manager.UpdateTruckCargo("1", 200)
+	manager.RemoveTruck("1")
+
+	added := nextEvent(t, events)
+	if added.Type != TruckAdded || added.Old != nil || added.New.Capacity != 100 {
+		t.Errorf("Expected truck added event with capacity 100, got %+v", added)
+	}
+
+	updated := nextEvent(t, events)
+	if updated.Type != CargoUpdated || updated.Old.Capacity != 100 || updated.New.Capacity != 200 {
+		t.Errorf("Expected cargo updated from 100 to 200, got %+v", updated)
+	}
+
+	removed := nextEvent(t, events)
+	if removed.Type != TruckRemoved || removed.Old.Capacity != 200 || removed.New != nil {
+		t.Errorf("Expected truck removed event with capacity 200, got %+v", removed)
+	}
+}
+
+func TestSlowSubscriberDoesNotBlock(t *testing.T) {
+	manager := NewFleetManager()
+	events := make(chan FleetEvent) // never read until the end
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			manager.UpdateTruckCargo("1", i)
+			manager.AddTruck("1", i)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected mutations to complete without a reader")
+	}
+
+	if ev := nextEvent(t, events); ev.Type != TruckAdded {
+		t.Errorf("Expected the first queued event to be truck added, got %v", ev.Type)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	manager := NewFleetManager()
+	events := make(chan FleetEvent, 10)
+	manager.Subscribe(events)
+	manager.Unsubscribe(events)
+
+	manager.AddTruck("1", 100)
+
+	select {
+	case ev := <-events:
+		t.Errorf("Expected no event after unsubscribe, got %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestAtomicBatchRollbackEmitsNothing(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("existing", 1)
+
+	events := make(chan FleetEvent, 10)
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	manager.AddTrucks([]Truck{{ID: "1"}, {ID: "existing"}}, AllOrNothing())
+	manager.AddTrucks([]Truck{{ID: "2"}}, AllOrNothing())
+
+	ev := nextEvent(t, events)
+	if ev.Type != TruckAdded || ev.TruckID != "2" {
+		t.Errorf("Expected only the committed batch to emit, got %+v", ev)
+	}
+}
+
+func TestLoadCargoEmitsEvent(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+
+	events := make(chan FleetEvent, 1)
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	manager.LoadCargo("1", 250)
+	ev := nextEvent(t, events)
+	if ev.Type != CargoUpdated || ev.Old.CurrentLoad != 0 || ev.New.CurrentLoad != 250 {
+		t.Errorf("Expected load change from 0 to 250, got %+v", ev)
+	}
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Errors for fleet import and export
+var (
+	ErrUnknownFormat    = errors.New("unknown format")
+	ErrUnknownMergeMode = errors.New("unknown merge mode")
+	ErrDuplicateRow     = errors.New("truck ID repeated in import")
+)
+
+// Format is a file format for fleet import and export
+type Format string
+
+// Supported formats
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// MergeMode decides what ImportFleet does with trucks that already exist
+type MergeMode string
+
+// Merge modes
+const (
+	// MergeUpdate adds new trucks and overwrites the capacity and load of existing ones
+	MergeUpdate MergeMode = "merge"
+	// MergeReplace makes the fleet match the import, removing trucks it doesn't list
+	MergeReplace MergeMode = "replace"
+	// MergeSkipDuplicates adds new trucks and leaves existing ones untouched
+	MergeSkipDuplicates MergeMode = "skip-duplicates"
+)
+
+// csvHeader lists the fixed CSV columns in export order. Custom fields
+// follow, one column each, named with csvCustomPrefix.
+var csvHeader = []string{"id", "capacity", "current_load", "driver_id", "route_id", "yard_id"}
+
+// csvCustomPrefix marks a CSV column as a custom field
+const csvCustomPrefix = "custom."
+
+// ImportRowError records why one row of an import failed
+type ImportRowError struct {
+	Row int // 1-based data row, not counting the CSV header
+	ID  string
+	Err error
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Imported int // trucks added or updated
+	Skipped  int // existing trucks left alone by MergeSkipDuplicates
+	Removed  int // trucks dropped by MergeReplace
+	Failed   []ImportRowError
+}
+
+// ExportFleet writes every truck, ordered by ID, to w
+func (tm *truckManager) ExportFleet(w io.Writer, format Format) error {
+	return tm.exportFleet(w, format, nil)
+}
+
+// exportFleet writes every truck, ordered by ID, to w with the policy's
+// columns left out or redacted. A nil policy exports everything.
+func (tm *truckManager) exportFleet(w io.Writer, format Format, policy *ExportPolicy) error {
+	if format != FormatJSON && format != FormatCSV {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	tm.RLock()
+	trucks := make([]Truck, 0, len(tm.ids))
+	fields := make(map[string]bool)
+	defs := tm.fieldsLocked(TruckEntity)
+	for _, def := range defs {
+		fields[def.Name] = true
+	}
+	for _, id := range tm.ids {
+		truck := tm.trucks[id].load().clone()
+		for name := range truck.Custom {
+			fields[name] = true
+		}
+		trucks = append(trucks, truck)
+	}
+	tm.RUnlock()
+
+	excluded, redacted := policy.columns(defs)
+	for i := range trucks {
+		redactTruck(&trucks[i], excluded, redacted)
+	}
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if len(excluded) == 0 && !redacted["location"] {
+			return enc.Encode(jsonFleetFile{Trucks: trucks})
+		}
+		return enc.Encode(policyJSON(trucks, excluded, redacted))
+	default:
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			if !excluded[csvCustomPrefix+name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		var fixed []string
+		for _, column := range csvHeader {
+			if !excluded[column] {
+				fixed = append(fixed, column)
+			}
+		}
+
+		cw := csv.NewWriter(w)
+		header := append([]string(nil), fixed...)
+		for _, name := range names {
+			header = append(header, csvCustomPrefix+name)
+		}
+		cw.Write(header)
+		for _, t := range trucks {
+			values := map[string]string{
+				"id":           t.ID,
+				"capacity":     strconv.Itoa(t.Capacity),
+				"current_load": strconv.Itoa(t.CurrentLoad),
+				"driver_id":    t.DriverID,
+				"route_id":     t.RouteID,
+				"yard_id":      t.YardID,
+			}
+			record := make([]string, 0, len(header))
+			for _, column := range fixed {
+				record = append(record, values[column])
+			}
+			for _, name := range names {
+				record = append(record, t.Custom[name])
+			}
+			cw.Write(record)
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// ImportFleet reads trucks from r and applies them to the fleet under one lock.
+// Rows that cannot be parsed or applied are reported and do not stop the
+// import. The returned error is for input that cannot be read at all.
+func (tm *truckManager) ImportFleet(r io.Reader, format Format, mode MergeMode) (ImportReport, error) {
+	rows, err := readImportRows(r, format, mode)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	return tm.importRows(context.Background(), rows, mode, max(len(rows), 1), nil)
+}
+
+// importChunkSize is the number of rows a background import applies per lock acquisition
+const importChunkSize = 500
+
+// readImportRows checks the mode and reads every row of an import
+func readImportRows(r io.Reader, format Format, mode MergeMode) ([]importRow, error) {
+	switch mode {
+	case MergeUpdate, MergeReplace, MergeSkipDuplicates:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMergeMode, mode)
+	}
+
+	switch format {
+	case FormatJSON:
+		return readJSONRows(r)
+	case FormatCSV:
+		return readCSVRows(r)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// importRows applies rows chunk rows per lock acquisition, checking ctx and
+// calling progress, if not nil, between chunks. A replace import removes
+// the trucks it leaves out together with the last chunk, so it removes
+// nothing if ctx cuts it short.
+func (tm *truckManager) importRows(ctx context.Context, rows []importRow, mode MergeMode, chunk int, progress func(done, total int)) (ImportReport, error) {
+	var report ImportReport
+	var result BatchResult
+	defer func() { tm.recordBatch("import", result) }()
+
+	listed := make(map[string]bool, len(rows))
+	for start := 0; ; start += chunk {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		end := min(start+chunk, len(rows))
+		tm.importChunk(rows[start:end], start, mode, listed, end == len(rows), &report, &result)
+		if progress != nil {
+			progress(end, len(rows))
+		}
+		if end == len(rows) {
+			return report, nil
+		}
+	}
+}
+
+// importChunk applies rows under one write lock. The first of them is row
+// offset+1 of the import. The last chunk of a replace import also removes
+// the trucks no chunk listed. Each truck changed and each row that failed is
+// added to result.
+func (tm *truckManager) importChunk(rows []importRow, offset int, mode MergeMode, listed map[string]bool, last bool, report *ImportReport, result *BatchResult) {
+	tm.Lock()
+	defer tm.Unlock()
+
+	for i, row := range rows {
+		if row.err == nil && listed[row.truck.ID] {
+			row.err = ErrDuplicateRow
+		}
+		if row.truck.ID != "" {
+			listed[row.truck.ID] = true
+		}
+		if row.err == nil {
+			before := tm.snapshotLocked(row.truck.ID)
+			skipped := report.Skipped
+			// The row is audited as one import below, not per field it touched
+			tm.auditHeld = true
+			row.err = tm.importLocked(row.truck, mode, report)
+			tm.auditHeld = false
+			// A row can fail after part of it was applied, so audit any change
+			if after := tm.snapshotLocked(row.truck.ID); (before == nil) != (after == nil) || (after != nil && after.Version != before.Version) {
+				tm.auditLocked(context.Background(), "import", row.truck.ID, before, after)
+			}
+			if row.err == nil && report.Skipped == skipped {
+				result.Succeeded = append(result.Succeeded, row.truck.ID)
+			}
+		}
+		if row.err != nil {
+			report.Failed = append(report.Failed, ImportRowError{Row: offset + i + 1, ID: row.truck.ID, Err: row.err})
+			result.Failed = append(result.Failed, BatchItemError{ID: row.truck.ID, Err: row.err})
+		}
+	}
+
+	if last && mode == MergeReplace {
+		// Copy the index, since removal edits it
+		for _, id := range append([]string(nil), tm.ids...) {
+			if listed[id] {
+				continue
+			}
+			removed, err := tm.recycleLocked(id)
+			if err != nil {
+				report.Failed = append(report.Failed, ImportRowError{ID: id, Err: err})
+				result.Failed = append(result.Failed, BatchItemError{ID: id, Err: err})
+				continue
+			}
+			tm.auditLocked(context.Background(), "import", id, &removed, nil)
+			result.Succeeded = append(result.Succeeded, id)
+			report.Removed++
+		}
+	}
+}
+
+// importLocked applies one imported truck. Callers must hold the write lock.
+func (tm *truckManager) importLocked(t Truck, mode MergeMode, report *ImportReport) error {
+	if _, exist := tm.trucks[t.ID]; !exist {
+		if err := tm.addLocked(t); err != nil {
+			return err
+		}
+		report.Imported++
+		return nil
+	}
+
+	if mode == MergeSkipDuplicates {
+		report.Skipped++
+		return nil
+	}
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	// Check the custom fields up front so a bad value doesn't leave the row half applied
+	custom := tm.trucks[t.ID].load().Custom
+	for name, value := range t.Custom {
+		var err error
+		if custom, err = tm.withFieldLocked(TruckEntity, custom, name, value); err != nil {
+			return err
+		}
+	}
+
+	spec := NewUpdateSpec().WithCapacity(t.Capacity).WithCurrentLoad(t.CurrentLoad)
+	if err := tm.updateLocked(t.ID, spec); err != nil {
+		return err
+	}
+	if len(t.Custom) > 0 && !maps.Equal(custom, tm.trucks[t.ID].load().Custom) {
+		if err := tm.setCustomLocked(t.ID, custom); err != nil {
+			return err
+		}
+	}
+	if len(t.Tags) > 0 && !maps.Equal(t.Tags, tm.trucks[t.ID].load().Tags) {
+		if err := tm.setTagsLocked(t.ID, maps.Clone(t.Tags)); err != nil {
+			return err
+		}
+	}
+	report.Imported++
+	return nil
+}
+
+// importRow is one parsed row; err is set if the row could not be parsed
+type importRow struct {
+	truck Truck
+	err   error
+}
+
+// readJSONRows parses the layout written by ExportFleet and JSONFileStorage
+func readJSONRows(r io.Reader) ([]importRow, error) {
+	var file jsonFleetFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("reading JSON import: %w", err)
+	}
+	rows := make([]importRow, len(file.Trucks))
+	for i, t := range file.Trucks {
+		rows[i] = importRow{truck: t}
+	}
+	return rows, nil
+}
+
+// readCSVRows parses CSV with a header row. Columns are matched by name in
+// any order; id and capacity are required, the rest are optional.
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		// An empty input must not pass for an empty fleet, or a replace import would remove every truck
+		return nil, errors.New("reading CSV header: missing header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	column := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		// Custom field names are case-sensitive; the fixed columns are not
+		if len(name) >= len(csvCustomPrefix) && strings.EqualFold(name[:len(csvCustomPrefix)], csvCustomPrefix) {
+			column[csvCustomPrefix+name[len(csvCustomPrefix):]] = i
+			continue
+		}
+		column[strings.ToLower(name)] = i
+	}
+	for _, required := range []string{"id", "capacity"} {
+		if _, ok := column[required]; !ok {
+			return nil, fmt.Errorf("reading CSV header: missing %q column", required)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		rows = append(rows, parseCSVRow(record, column))
+	}
+}
+
+// parseCSVRow converts one CSV record into a truck
+func parseCSVRow(record []string, column map[string]int) importRow {
+	field := func(name string) string {
+		i, ok := column[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := importRow{truck: Truck{ID: field("id"), DriverID: field("driver_id"), RouteID: field("route_id"), YardID: field("yard_id")}}
+	for name := range column {
+		if custom, ok := strings.CutPrefix(name, csvCustomPrefix); ok {
+			if row.truck.Custom == nil {
+				row.truck.Custom = make(map[string]string)
+			}
+			row.truck.Custom[custom] = field(name)
+		}
+	}
+	capacity, err := strconv.Atoi(field("capacity"))
+	if err != nil {
+		row.err = fmt.Errorf("%w: capacity %q", ErrInvalidCargo, field("capacity"))
+		return row
+	}
+	row.truck.Capacity = capacity
+	if load := field("current_load"); load != "" {
+		if row.truck.CurrentLoad, err = strconv.Atoi(load); err != nil {
+			row.err = fmt.Errorf("%w: current_load %q", ErrInvalidCargo, load)
+		}
+	}
+	return row
+}
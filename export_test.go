@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatCSV} {
+		source := NewFleetManager()
+		source.AddTruck("1", 100)
+		source.AddTruck("2", 200)
+		source.LoadCargo("2", 50)
+
+		var buf bytes.Buffer
+		if err := source.ExportFleet(&buf, format); err != nil {
+			t.Fatalf("%s: Expected no error, got %v", format, err)
+		}
+
+		target := NewFleetManager()
+		report, err := target.ImportFleet(&buf, format, MergeUpdate)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", format, err)
+		}
+		if report.Imported != 2 || len(report.Failed) != 0 {
+			t.Errorf("%s: Expected 2 imported and none failed, got %+v", format, report)
+		}
+		truck, _ := target.GetTruck("2")
+		if truck.Capacity != 200 || truck.CurrentLoad != 50 {
+			t.Errorf("%s: Expected capacity 200 and load 50, got %+v", format, truck)
+		}
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	var buf bytes.Buffer
+	manager.ExportFleet(&buf, FormatCSV)
+	expected := "id,capacity,current_load,driver_id,route_id,yard_id\n1,100,0,,,\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestImportMergeModes(t *testing.T) {
+	input := "id,capacity\n1,500\n3,300\n"
+
+	tests := []struct {
+		mode            MergeMode
+		imported        int
+		skipped         int
+		removed         int
+		capacityOfOne   int
+		truckTwoRemains bool
+	}{
+		{MergeUpdate, 2, 0, 0, 500, true},
+		{MergeSkipDuplicates, 1, 1, 0, 100, true},
+		{MergeReplace, 2, 0, 1, 500, false},
+	}
+
+	for _, tt := range tests {
+		manager := NewFleetManager()
+		manager.AddTruck("1", 100)
+		manager.AddTruck("2", 200)
+
+		report, err := manager.ImportFleet(strings.NewReader(input), FormatCSV, tt.mode)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", tt.mode, err)
+		}
+		if report.Imported != tt.imported || report.Skipped != tt.skipped || report.Removed != tt.removed {
+			t.Errorf("%s: Expected %d imported, %d skipped, %d removed, got %+v", tt.mode, tt.imported, tt.skipped, tt.removed, report)
+		}
+		truck, _ := manager.GetTruck("1")
+		if truck.Capacity != tt.capacityOfOne {
+			t.Errorf("%s: Expected truck 1 capacity %d, got %d", tt.mode, tt.capacityOfOne, truck.Capacity)
+		}
+		if manager.Exists("2") != tt.truckTwoRemains {
+			t.Errorf("%s: Expected truck 2 present to be %v", tt.mode, tt.truckTwoRemains)
+		}
+	}
+}
+
+func TestImportReportsFailedRows(t *testing.T) {
+	manager := NewFleetManager()
+	input := "capacity,id,current_load\n100,1,0\nlots,2,0\n100,3,200\n100,1,0\n-5,4,\n"
+
+	report, err := manager.ImportFleet(strings.NewReader(input), FormatCSV, MergeUpdate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Imported != 1 || len(report.Failed) != 4 {
+		t.Fatalf("Expected 1 imported and 4 failed, got %+v", report)
+	}
+
+	expected := []struct {
+		row int
+		err error
+	}{
+		{2, ErrInvalidCargo},
+		{3, ErrCapacityExceeded},
+		{4, ErrDuplicateRow},
+		{5, ErrInvalidCargo},
+	}
+	for i, e := range expected {
+		got := report.Failed[i]
+		if got.Row != e.row || !errors.Is(got.Err, e.err) {
+			t.Errorf("Expected row %d to fail with %v, got row %d: %v", e.row, e.err, got.Row, got.Err)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	manager := NewFleetManager()
+
+	if _, err := manager.ImportFleet(strings.NewReader(""), FormatCSV, "overwrite"); !errors.Is(err, ErrUnknownMergeMode) {
+		t.Errorf("Expected unknown merge mode error, got %v", err)
+	}
+	if _, err := manager.ImportFleet(strings.NewReader(""), "xlsx", MergeUpdate); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+	if _, err := manager.ImportFleet(strings.NewReader("name\nx\n"), FormatCSV, MergeUpdate); err == nil {
+		t.Errorf("Expected an error for a CSV without an id column")
+	}
+	if _, err := manager.ImportFleet(strings.NewReader("{"), FormatJSON, MergeUpdate); err == nil {
+		t.Errorf("Expected an error for malformed JSON")
+	}
+}
+
+func TestImportEmptyCSVReplaceKeepsFleet(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+
+	if _, err := manager.ImportFleet(strings.NewReader(""), FormatCSV, MergeReplace); err == nil {
+		t.Errorf("Expected an error for a CSV without a header row")
+	}
+	if !manager.Exists("1") || !manager.Exists("2") {
+		t.Errorf("Expected the fleet unchanged after a rejected import")
+	}
+	if removed := manager.ListRemovedTrucks(); len(removed) != 0 {
+		t.Errorf("Expected nothing in the recycle bin, got %+v", removed)
+	}
+}
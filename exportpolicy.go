@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidPolicy is returned for an export policy naming a column it cannot act on
+var ErrInvalidPolicy = errors.New("invalid export policy")
+
+// RoleAdmin is the role whose exports are never redacted
+const RoleAdmin = "admin"
+
+// redactedValue replaces the contents of a redacted column
+const redactedValue = "[redacted]"
+
+// ExportPolicy decides which columns an export leaves out or redacts.
+// Columns are named as in the CSV header: driver_id, route_id, yard_id,
+// capacity, current_load, or custom.<name> for a custom field. The same
+// names are the keys of a JSON export, which also has location, tags,
+// fuel_capacity, fuel_level and version; CSV exports never include those.
+// id is always exported. Numbers can only be excluded. A redacted location
+// becomes the string [redacted] and redacted tags keep their keys.
+type ExportPolicy struct {
+	Exclude []string // columns left out of the export
+	Redact  []string // columns whose values are replaced with [redacted]
+	// RedactSensitive also redacts every custom field defined as Sensitive
+	RedactSensitive bool
+}
+
+// DefaultExportPolicy applies to roles without a policy of their own: the
+// driver assignment, the GPS position and sensitive custom fields are redacted
+var DefaultExportPolicy = ExportPolicy{Redact: []string{"driver_id", "location"}, RedactSensitive: true}
+
+// validate checks every column the policy names
+func (p ExportPolicy) validate() error {
+	for _, column := range p.Exclude {
+		if err := checkPolicyColumn(column, false); err != nil {
+			return err
+		}
+	}
+	for _, column := range p.Redact {
+		if err := checkPolicyColumn(column, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPolicyColumn reports whether a policy may exclude, or redact, column
+func checkPolicyColumn(column string, redact bool) error {
+	if name, ok := strings.CutPrefix(column, csvCustomPrefix); ok && name != "" {
+		return nil
+	}
+	switch column {
+	case "driver_id", "route_id", "yard_id", "location", "tags":
+		return nil
+	case "capacity", "current_load", "fuel_capacity", "fuel_level", "version":
+		if !redact {
+			return nil
+		}
+		return fmt.Errorf("%w: %s is a number and can only be excluded", ErrInvalidPolicy, column)
+	case "id":
+		return fmt.Errorf("%w: id is always exported", ErrInvalidPolicy)
+	default:
+		return fmt.Errorf("%w: unknown column %q", ErrInvalidPolicy, column)
+	}
+}
+
+// columns returns the sets of excluded and redacted columns, resolving
+// RedactSensitive against defs. A nil policy excludes and redacts nothing.
+func (p *ExportPolicy) columns(defs []FieldDef) (excluded, redacted map[string]bool) {
+	if p == nil {
+		return nil, nil
+	}
+	excluded = make(map[string]bool, len(p.Exclude))
+	for _, column := range p.Exclude {
+		excluded[column] = true
+	}
+	redacted = make(map[string]bool, len(p.Redact))
+	for _, column := range p.Redact {
+		redacted[column] = true
+	}
+	if p.RedactSensitive {
+		for _, def := range defs {
+			if def.Sensitive {
+				redacted[csvCustomPrefix+def.Name] = true
+			}
+		}
+	}
+	return excluded, redacted
+}
+
+// redactTruck applies the columns to a truck about to be exported. The
+// truck must be a clone, since its custom fields are modified.
+func redactTruck(t *Truck, excluded, redacted map[string]bool) {
+	redact := func(column string, value *string) {
+		if redacted[column] && *value != "" {
+			*value = redactedValue
+		}
+	}
+	redact("driver_id", &t.DriverID)
+	redact("route_id", &t.RouteID)
+	redact("yard_id", &t.YardID)
+	if redacted["tags"] {
+		for key, value := range t.Tags {
+			redact("tags", &value)
+			t.Tags[key] = value
+		}
+	}
+	for name, value := range t.Custom {
+		column := csvCustomPrefix + name
+		switch {
+		case excluded[column]:
+			delete(t.Custom, name)
+		case redacted[column]:
+			redact(column, &value)
+			t.Custom[name] = value
+		}
+	}
+	if len(t.Custom) == 0 {
+		t.Custom = nil
+	}
+}
+
+// policyJSON converts trucks to JSON objects without the excluded fixed
+// columns and with a redacted location, in the layout of jsonFleetFile.
+// Excluded custom fields and other redactions are already applied to the
+// trucks.
+func policyJSON(trucks []Truck, excluded, redacted map[string]bool) map[string][]map[string]json.RawMessage {
+	redactedLocation, _ := json.Marshal(redactedValue)
+	objects := make([]map[string]json.RawMessage, 0, len(trucks))
+	for _, t := range trucks {
+		// Trucks always marshal, and their keys match the column names
+		data, _ := json.Marshal(t)
+		var object map[string]json.RawMessage
+		json.Unmarshal(data, &object)
+		for column := range excluded {
+			delete(object, column)
+		}
+		if _, exist := object["location"]; exist && redacted["location"] {
+			object["location"] = redactedLocation
+		}
+		objects = append(objects, object)
+	}
+	return map[string][]map[string]json.RawMessage{"trucks": objects}
+}
+
+// SetExportPolicy sets the policy applied to exports made with ExportFleetAs
+// for role. The admin role always exports everything.
+func (tm *truckManager) SetExportPolicy(role string, policy ExportPolicy) error {
+	if role == "" || role == RoleAdmin {
+		return fmt.Errorf("%w: role %q cannot be given a policy", ErrInvalidPolicy, role)
+	}
+	if err := policy.validate(); err != nil {
+		return err
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	policy.Exclude = slices.Clone(policy.Exclude)
+	policy.Redact = slices.Clone(policy.Redact)
+	tm.exportPolicies[role] = policy
+	return nil
+}
+
+// ExportPolicies returns a copy of the policies set with SetExportPolicy, by role
+func (tm *truckManager) ExportPolicies() map[string]ExportPolicy {
+	tm.RLock()
+	defer tm.RUnlock()
+	return maps.Clone(tm.exportPolicies)
+}
+
+// ExportFleetAs is ExportFleet on behalf of role. Admins get every column;
+// other roles get their own policy, or DefaultExportPolicy if they have none.
+func (tm *truckManager) ExportFleetAs(w io.Writer, format Format, role string) error {
+	if role == RoleAdmin {
+		return tm.exportFleet(w, format, nil)
+	}
+
+	tm.RLock()
+	policy, exist := tm.exportPolicies[role]
+	tm.RUnlock()
+	if !exist {
+		policy = DefaultExportPolicy
+	}
+	return tm.exportFleet(w, format, &policy)
+}
+
+// ExportFleetWithPolicy is ExportFleet with policy applied
+func (tm *truckManager) ExportFleetWithPolicy(w io.Writer, format Format, policy ExportPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	return tm.exportFleet(w, format, &policy)
+}
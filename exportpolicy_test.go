@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newPolicyFleet returns a fleet with a driver and a sensitive custom field set
+func newPolicyFleet(t *testing.T) *truckManager {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+	manager.DefineField(TruckEntity, FieldDef{Name: "Phone", Type: FieldString, Sensitive: true})
+	manager.DefineField(TruckEntity, FieldDef{Name: "Plate", Type: FieldString})
+	manager.SetTruckField("1", "Phone", "0700 000000")
+	manager.SetTruckField("1", "Plate", "KDA 123X")
+	if truck, _ := manager.GetTruck("1"); truck.DriverID != "d1" {
+		t.Fatalf("Expected driver d1 on truck 1, got %q", truck.DriverID)
+	}
+	return manager
+}
+
+func TestExportFleetAsAdmin(t *testing.T) {
+	manager := newPolicyFleet(t)
+
+	var admin, plain bytes.Buffer
+	manager.ExportFleetAs(&admin, FormatCSV, RoleAdmin)
+	manager.ExportFleet(&plain, FormatCSV)
+	if admin.String() != plain.String() {
+		t.Errorf("Expected admin export to match the full export, got %q", admin.String())
+	}
+}
+
+func TestExportFleetAsDefaultPolicy(t *testing.T) {
+	manager := newPolicyFleet(t)
+
+	var buf bytes.Buffer
+	if err := manager.ExportFleetAs(&buf, FormatCSV, "dispatcher"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "id,capacity,current_load,driver_id,route_id,yard_id,custom.Phone,custom.Plate\n" +
+		"1,100,0,[redacted],,,[redacted],KDA 123X\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestExportFleetAsRolePolicy(t *testing.T) {
+	manager := newPolicyFleet(t)
+	err := manager.SetExportPolicy("auditor", ExportPolicy{Exclude: []string{"driver_id", "custom.Phone", "current_load"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	manager.ExportFleetAs(&buf, FormatCSV, "auditor")
+	expected := "id,capacity,route_id,yard_id,custom.Plate\n1,100,,,KDA 123X\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	buf.Reset()
+	manager.ExportFleetAs(&buf, FormatJSON, "auditor")
+	var file struct {
+		Trucks []map[string]any `json:"trucks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &file); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	truck := file.Trucks[0]
+	if _, ok := truck["current_load"]; ok {
+		t.Errorf("Expected current_load to be left out, got %v", truck)
+	}
+	if _, ok := truck["driver_id"]; ok {
+		t.Errorf("Expected driver_id to be left out, got %v", truck)
+	}
+	custom, _ := truck["custom"].(map[string]any)
+	if _, ok := custom["Phone"]; ok || custom["Plate"] != "KDA 123X" {
+		t.Errorf("Expected only Plate among custom fields, got %v", custom)
+	}
+}
+
+func TestExportFleetWithPolicyRedactsJSON(t *testing.T) {
+	manager := newPolicyFleet(t)
+
+	var buf bytes.Buffer
+	manager.ExportFleetWithPolicy(&buf, FormatJSON, ExportPolicy{Redact: []string{"custom.Plate"}})
+	if strings.Contains(buf.String(), "KDA 123X") || !strings.Contains(buf.String(), redactedValue) {
+		t.Errorf("Expected the plate to be redacted, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "0700 000000") {
+		t.Errorf("Expected the phone to be kept without RedactSensitive, got %s", buf.String())
+	}
+
+	// The fleet itself is untouched
+	truck, _ := manager.GetTruck("1")
+	if truck.Custom["Plate"] != "KDA 123X" {
+		t.Errorf("Expected the stored plate to be kept, got %v", truck.Custom)
+	}
+}
+
+func TestExportFleetWithPolicyRedactsLocation(t *testing.T) {
+	manager := newPolicyFleet(t)
+	manager.UpdateLocation("1", -1.2921, 36.8219, time.Now())
+	manager.SetTag("1", "depot", "westlands")
+	manager.SetFuelTank("1", 400)
+
+	var buf bytes.Buffer
+	policy := ExportPolicy{Redact: []string{"location", "tags"}, Exclude: []string{"fuel_capacity", "version"}}
+	if err := manager.ExportFleetWithPolicy(&buf, FormatJSON, policy); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var file struct {
+		Trucks []map[string]json.RawMessage `json:"trucks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &file); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	truck := file.Trucks[0]
+	if string(truck["location"]) != `"[redacted]"` {
+		t.Errorf("Expected the location to be redacted, got %s", truck["location"])
+	}
+	var tags map[string]string
+	json.Unmarshal(truck["tags"], &tags)
+	if tags["depot"] != redactedValue {
+		t.Errorf("Expected the tag value to be redacted, got %s", truck["tags"])
+	}
+	if _, exist := truck["fuel_capacity"]; exist {
+		t.Errorf("Expected fuel_capacity to be excluded, got %s", truck["fuel_capacity"])
+	}
+	if _, exist := truck["version"]; exist {
+		t.Errorf("Expected version to be excluded, got %s", truck["version"])
+	}
+
+	// Roles without a policy don't see positions either
+	buf.Reset()
+	manager.ExportFleetAs(&buf, FormatJSON, "dispatcher")
+	if strings.Contains(buf.String(), "36.8219") {
+		t.Errorf("Expected the default policy to redact the location, got %s", buf.String())
+	}
+}
+
+func TestExportPolicyValidation(t *testing.T) {
+	manager := NewFleetManager()
+
+	cases := []ExportPolicy{
+		{Exclude: []string{"id"}},
+		{Redact: []string{"capacity"}},
+		{Exclude: []string{"colour"}},
+		{Redact: []string{"custom."}},
+		{Redact: []string{"fuel_level"}},
+		{Redact: []string{"version"}},
+	}
+	for _, policy := range cases {
+		if err := manager.SetExportPolicy("auditor", policy); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("Expected ErrInvalidPolicy for %+v, got %v", policy, err)
+		}
+	}
+	if err := manager.SetExportPolicy(RoleAdmin, ExportPolicy{}); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("Expected ErrInvalidPolicy for the admin role, got %v", err)
+	}
+	if len(manager.ExportPolicies()) != 0 {
+		t.Errorf("Expected no policies, got %v", manager.ExportPolicies())
+	}
+}
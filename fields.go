@@ -0,0 +1,265 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors for custom fields
+var (
+	ErrUnknownField      = errors.New("unknown custom field")
+	ErrFieldExist        = errors.New("custom field already defined")
+	ErrInvalidField      = errors.New("invalid custom field definition")
+	ErrInvalidFieldValue = errors.New("invalid custom field value")
+)
+
+// FieldEntity names the kind of record a custom field is defined on
+type FieldEntity string
+
+// Entities that accept custom fields
+const (
+	TruckEntity  FieldEntity = "truck"
+	DriverEntity FieldEntity = "driver"
+)
+
+// FieldType is the type a custom field's values must have
+type FieldType string
+
+// Custom field types. Values are stored as strings in a canonical form:
+// numbers as written by strconv.FormatFloat and dates as YYYY-MM-DD.
+const (
+	FieldString FieldType = "string"
+	FieldNumber FieldType = "number"
+	FieldDate   FieldType = "date"
+	FieldEnum   FieldType = "enum"
+)
+
+// FieldDef defines a custom field. Values lists the allowed values of an
+// enum field. Sensitive fields, such as a driver's phone number, are
+// redacted by DefaultExportPolicy.
+type FieldDef struct {
+	Name      string
+	Type      FieldType
+	Values    []string
+	Sensitive bool
+}
+
+// validate checks the definition itself
+func (d FieldDef) validate() error {
+	if d.Name == "" || strings.ContainsAny(d.Name, ", \t\n") {
+		return fmt.Errorf("%w: name %q", ErrInvalidField, d.Name)
+	}
+	switch d.Type {
+	case FieldString, FieldNumber, FieldDate:
+	case FieldEnum:
+		if len(d.Values) == 0 {
+			return fmt.Errorf("%w: enum %s has no values", ErrInvalidField, d.Name)
+		}
+	default:
+		return fmt.Errorf("%w: type %q", ErrInvalidField, d.Type)
+	}
+	return nil
+}
+
+// normalize checks value against the field's type and returns its canonical form
+func (d FieldDef) normalize(value string) (string, error) {
+	switch d.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidFieldValue, d.Name, value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case FieldDate:
+		t, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("%w: %s must be a YYYY-MM-DD date, got %q", ErrInvalidFieldValue, d.Name, value)
+		}
+		return t.Format(time.DateOnly), nil
+	case FieldEnum:
+		if !slices.Contains(d.Values, value) {
+			return "", fmt.Errorf("%w: %s must be one of %s, got %q", ErrInvalidFieldValue, d.Name, strings.Join(d.Values, ", "), value)
+		}
+	}
+	return value, nil
+}
+
+// DefineField adds a custom field to trucks or drivers. Definitions are held
+// in memory only; values already stored on records survive a restart, but
+// are checked again only when next written.
+func (tm *truckManager) DefineField(entity FieldEntity, def FieldDef) error {
+	if entity != TruckEntity && entity != DriverEntity {
+		return fmt.Errorf("%w: entity %q", ErrInvalidField, entity)
+	}
+	if err := def.validate(); err != nil {
+		return err
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if tm.fieldDefs[entity] == nil {
+		tm.fieldDefs[entity] = make(map[string]FieldDef)
+	}
+	if _, exist := tm.fieldDefs[entity][def.Name]; exist {
+		return ErrFieldExist
+	}
+	def.Values = slices.Clone(def.Values)
+	tm.fieldDefs[entity][def.Name] = def
+	return nil
+}
+
+// Fields returns the custom fields defined on an entity, ordered by name
+func (tm *truckManager) Fields(entity FieldEntity) []FieldDef {
+	tm.RLock()
+	defer tm.RUnlock()
+	return tm.fieldsLocked(entity)
+}
+
+// fieldsLocked is Fields for callers already holding the lock
+func (tm *truckManager) fieldsLocked(entity FieldEntity) []FieldDef {
+	defs := make([]FieldDef, 0, len(tm.fieldDefs[entity]))
+	for _, def := range tm.fieldDefs[entity] {
+		def.Values = slices.Clone(def.Values)
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// SetTruckField sets a custom field on a truck. An empty value clears it.
+func (tm *truckManager) SetTruckField(truckID, name, value string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	custom, err := tm.withFieldLocked(TruckEntity, truck.Custom, name, value)
+	if err != nil {
+		return err
+	}
+	return tm.setCustomLocked(truckID, custom)
+}
+
+// setCustomLocked replaces the custom fields of an existing truck. Callers must hold the write lock.
+func (tm *truckManager) setCustomLocked(truckID string, custom map[string]string) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.Custom = custom
+	updated.Version++
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+	old := *truck
+	tm.trucks[truckID].store(&updated)
+	tm.emit(FieldsChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("field", truckID, &old, &updated)
+	return nil
+}
+
+// SetDriverField sets a custom field on a driver. An empty value clears it.
+func (tm *truckManager) SetDriverField(driverID, name, value string) error {
+	if driverID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	driver, exist := tm.drivers[driverID]
+	if !exist {
+		return ErrDriverNotFound
+	}
+	custom, err := tm.withFieldLocked(DriverEntity, driver.Custom, name, value)
+	if err != nil {
+		return err
+	}
+	driver.Custom = custom
+	return nil
+}
+
+// withFieldLocked returns a copy of custom with the field set to the
+// canonical form of value, or removed if value is empty. The original map is
+// never modified, since copies handed out earlier may share it. Callers must
+// hold the lock.
+func (tm *truckManager) withFieldLocked(entity FieldEntity, custom map[string]string, name, value string) (map[string]string, error) {
+	def, exist := tm.fieldDefs[entity][name]
+	if !exist {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownField, entity, name)
+	}
+
+	updated := maps.Clone(custom)
+	if value == "" {
+		delete(updated, name)
+		if len(updated) == 0 {
+			return nil, nil
+		}
+		return updated, nil
+	}
+
+	normalized, err := def.normalize(value)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		updated = make(map[string]string, 1)
+	}
+	updated[name] = normalized
+	return updated, nil
+}
+
+// normalizeFieldsLocked checks every custom field of a record being written,
+// returning the fields in canonical form. Empty values are dropped. Callers
+// must hold the lock.
+func (tm *truckManager) normalizeFieldsLocked(entity FieldEntity, custom map[string]string) (map[string]string, error) {
+	if len(custom) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]string, len(custom))
+	for name, value := range custom {
+		if value == "" {
+			continue
+		}
+		def, exist := tm.fieldDefs[entity][name]
+		if !exist {
+			return nil, fmt.Errorf("%w: %s %s", ErrUnknownField, entity, name)
+		}
+		v, err := def.normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = v
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// FieldEquals matches trucks whose custom field has exactly value, in its canonical form
+func FieldEquals(name, value string) TruckFilter {
+	return func(t Truck) bool {
+		v, ok := t.Custom[name]
+		return ok && v == value
+	}
+}
+
+// HasField matches trucks with any value set for the custom field
+func HasField(name string) TruckFilter {
+	return func(t Truck) bool {
+		_, ok := t.Custom[name]
+		return ok
+	}
+}
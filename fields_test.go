@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefineField(t *testing.T) {
+	manager := NewFleetManager()
+
+	if err := manager.DefineField(TruckEntity, FieldDef{Name: "plate", Type: FieldString}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.DefineField(TruckEntity, FieldDef{Name: "plate", Type: FieldString}); err != ErrFieldExist {
+		t.Errorf("Expected field exist error, got %v", err)
+	}
+	if err := manager.DefineField(TruckEntity, FieldDef{Name: "fuel", Type: FieldEnum}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected an enum without values to be invalid, got %v", err)
+	}
+	if err := manager.DefineField("shipment", FieldDef{Name: "x", Type: FieldString}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected an unknown entity to be invalid, got %v", err)
+	}
+	if err := manager.DefineField(TruckEntity, FieldDef{Name: "axles", Type: "integer"}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected an unknown type to be invalid, got %v", err)
+	}
+
+	// The same name may be defined on another entity
+	if err := manager.DefineField(DriverEntity, FieldDef{Name: "plate", Type: FieldString}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestSetTruckField(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.DefineField(TruckEntity, FieldDef{Name: "axles", Type: FieldNumber})
+	manager.DefineField(TruckEntity, FieldDef{Name: "inspected", Type: FieldDate})
+	manager.DefineField(TruckEntity, FieldDef{Name: "fuel", Type: FieldEnum, Values: []string{"diesel", "electric"}})
+
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"axles", "3.0", nil},
+		{"axles", "three", ErrInvalidFieldValue},
+		{"inspected", "2026-03-01", nil},
+		{"inspected", "01/03/2026", ErrInvalidFieldValue},
+		{"fuel", "electric", nil},
+		{"fuel", "petrol", ErrInvalidFieldValue},
+		{"colour", "red", ErrUnknownField},
+	}
+	for _, tt := range tests {
+		if err := manager.SetTruckField("1", tt.name, tt.value); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v setting %s to %q, got %v", tt.err, tt.name, tt.value, err)
+		}
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Custom["axles"] != "3" || truck.Custom["inspected"] != "2026-03-01" || truck.Custom["fuel"] != "electric" {
+		t.Errorf("Expected canonical custom values, got %v", truck.Custom)
+	}
+
+	// Copies handed out are independent of the fleet
+	truck.Custom["fuel"] = "diesel"
+	again, _ := manager.GetTruck("1")
+	if again.Custom["fuel"] != "electric" {
+		t.Errorf("Expected stored value to be unchanged, got %q", again.Custom["fuel"])
+	}
+
+	manager.SetTruckField("1", "fuel", "")
+	again, _ = manager.GetTruck("1")
+	if _, ok := again.Custom["fuel"]; ok {
+		t.Errorf("Expected an empty value to clear the field, got %v", again.Custom)
+	}
+}
+
+func TestAddTruckValidatesFields(t *testing.T) {
+	manager := NewFleetManager()
+	manager.DefineField(TruckEntity, FieldDef{Name: "axles", Type: FieldNumber})
+
+	result, _ := manager.AddTrucks([]Truck{
+		{ID: "1", Capacity: 100, Custom: map[string]string{"axles": "2"}},
+		{ID: "2", Capacity: 100, Custom: map[string]string{"axles": "many"}},
+		{ID: "3", Capacity: 100, Custom: map[string]string{"colour": "red"}},
+	})
+	if len(result.Succeeded) != 1 || len(result.Failed) != 2 {
+		t.Errorf("Expected only truck 1 to be added, got %+v", result)
+	}
+}
+
+func TestSetDriverField(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddDriver("d1", "Amina")
+	manager.DefineField(DriverEntity, FieldDef{Name: "licence_expiry", Type: FieldDate})
+
+	if err := manager.SetDriverField("d1", "licence_expiry", "2027-06-30"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	driver, _ := manager.GetDriver("d1")
+	if driver.Custom["licence_expiry"] != "2027-06-30" {
+		t.Errorf("Expected licence expiry to be set, got %v", driver.Custom)
+	}
+	if err := manager.SetDriverField("d2", "licence_expiry", "2027-06-30"); err != ErrDriverNotFound {
+		t.Errorf("Expected driver not found error, got %v", err)
+	}
+}
+
+func TestFieldFilters(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.DefineField(TruckEntity, FieldDef{Name: "fuel", Type: FieldEnum, Values: []string{"diesel", "electric"}})
+	manager.SetTruckField("1", "fuel", "electric")
+
+	if n := manager.Count(FieldEquals("fuel", "electric")); n != 1 {
+		t.Errorf("Expected 1 electric truck, got %d", n)
+	}
+	if n := manager.Count(Not(HasField("fuel"))); n != 1 {
+		t.Errorf("Expected 1 truck without fuel set, got %d", n)
+	}
+}
+
+func TestCustomFieldsExportImport(t *testing.T) {
+	source := NewFleetManager()
+	source.AddTruck("1", 100)
+	source.AddTruck("2", 100)
+	source.DefineField(TruckEntity, FieldDef{Name: "Plate", Type: FieldString})
+	source.SetTruckField("1", "Plate", "KDA 123X")
+
+	var buf bytes.Buffer
+	source.ExportFleet(&buf, FormatCSV)
+	if !strings.HasPrefix(buf.String(), "id,capacity,current_load,driver_id,route_id,yard_id,custom.Plate\n") {
+		t.Errorf("Expected a custom column in the header, got %q", buf.String())
+	}
+
+	target := NewFleetManager()
+	target.DefineField(TruckEntity, FieldDef{Name: "Plate", Type: FieldString})
+	report, _ := target.ImportFleet(&buf, FormatCSV, MergeUpdate)
+	if report.Imported != 2 || len(report.Failed) != 0 {
+		t.Fatalf("Expected 2 imported, got %+v", report)
+	}
+	truck, _ := target.GetTruck("1")
+	if truck.Custom["Plate"] != "KDA 123X" {
+		t.Errorf("Expected plate to be imported, got %v", truck.Custom)
+	}
+
+	// Merging an existing truck checks the values against the definitions
+	input := "id,capacity,custom.Plate\n1,100,\n"
+	report, _ = target.ImportFleet(strings.NewReader(input), FormatCSV, MergeUpdate)
+	truck, _ = target.GetTruck("1")
+	if report.Imported != 1 || truck.Custom != nil {
+		t.Errorf("Expected an empty cell to clear the field, got %+v and %v", report, truck.Custom)
+	}
+	input = "id,capacity,custom.Colour\n1,100,red\n"
+	report, _ = target.ImportFleet(strings.NewReader(input), FormatCSV, MergeUpdate)
+	if len(report.Failed) != 1 || !errors.Is(report.Failed[0].Err, ErrUnknownField) {
+		t.Errorf("Expected an unknown field to fail the row, got %+v", report)
+	}
+}
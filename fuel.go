@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Errors for fuel tracking
+var (
+	ErrInvalidFuel      = errors.New("invalid fuel value")
+	ErrFuelTankOverflow = errors.New("refuel exceeds tank capacity")
+	ErrInsufficientFuel = errors.New("not enough fuel in tank")
+)
+
+// FuelRecord is one refuel or trip recorded for a truck
+type FuelRecord struct {
+	TruckID string
+	Time    time.Time
+	Liters  float64 // fuel added by a refuel, or used by a trip
+	Cost    float64 // what a refuel cost; zero for trips
+	Km      float64 // distance driven on a trip; zero for refuels
+	Refuel  bool
+}
+
+// TruckFuelReport summarizes one truck's fuel records over a period
+type TruckFuelReport struct {
+	TruckID        string
+	Km             float64
+	LitersUsed     float64
+	LitersRefueled float64
+	Cost           float64
+	// KmPerLiter is Km / LitersUsed, or 0 if no fuel was used
+	KmPerLiter float64
+	// CostPerLiter is Cost / LitersRefueled, or 0 if the truck wasn't refueled
+	CostPerLiter float64
+}
+
+// FuelReport summarizes fuel records in [From, To) per truck, ordered by
+// ID, and across the fleet
+type FuelReport struct {
+	From, To time.Time
+	Trucks   []TruckFuelReport
+	Fleet    TruckFuelReport // totals; TruckID is empty
+}
+
+// add accumulates a record into the report
+func (r *TruckFuelReport) add(rec FuelRecord) {
+	if rec.Refuel {
+		r.LitersRefueled += rec.Liters
+		r.Cost += rec.Cost
+		return
+	}
+	r.Km += rec.Km
+	r.LitersUsed += rec.Liters
+}
+
+// finish fills in the ratios from the totals
+func (r *TruckFuelReport) finish() {
+	if r.LitersUsed > 0 {
+		r.KmPerLiter = r.Km / r.LitersUsed
+	}
+	if r.LitersRefueled > 0 {
+		r.CostPerLiter = r.Cost / r.LitersRefueled
+	}
+}
+
+// SetFuelTank sets the size of a truck's fuel tank in liters. A level above
+// the new size is lowered to it.
+func (tm *truckManager) SetFuelTank(truckID string, liters float64) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+	if liters < 0 || !finite(liters) {
+		return ErrInvalidFuel
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	return tm.setFuelLocked(truckID, liters, min(truck.FuelLevel, liters))
+}
+
+// RecordRefuel records liters of fuel bought for cost and raises the truck's
+// fuel level. A refuel that would overfill the tank is rejected with
+// ErrFuelTankOverflow, so set the tank size with SetFuelTank first.
+func (tm *truckManager) RecordRefuel(truckID string, liters, cost float64) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+	if liters <= 0 || cost < 0 || !finite(liters, cost) {
+		return ErrInvalidFuel
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	level := truck.FuelLevel + liters
+	if level > truck.FuelCapacity {
+		return fmt.Errorf("%w: %s has room for %g liters, got %g", ErrFuelTankOverflow, truckID, truck.FuelCapacity-truck.FuelLevel, liters)
+	}
+	if err := tm.setFuelLocked(truckID, truck.FuelCapacity, level); err != nil {
+		return err
+	}
+	tm.fuel[truckID] = append(tm.fuel[truckID], FuelRecord{TruckID: truckID, Time: time.Now(), Liters: liters, Cost: cost, Refuel: true})
+	return nil
+}
+
+// RecordTrip records a trip of km kilometers that burned litersUsed liters
+// and lowers the truck's fuel level
+func (tm *truckManager) RecordTrip(truckID string, km, litersUsed float64) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+	if km < 0 || litersUsed < 0 || !finite(km, litersUsed) {
+		return ErrInvalidFuel
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if litersUsed > truck.FuelLevel {
+		return fmt.Errorf("%w: %s has %g liters, trip used %g", ErrInsufficientFuel, truckID, truck.FuelLevel, litersUsed)
+	}
+	if err := tm.setFuelLocked(truckID, truck.FuelCapacity, truck.FuelLevel-litersUsed); err != nil {
+		return err
+	}
+	tm.fuel[truckID] = append(tm.fuel[truckID], FuelRecord{TruckID: truckID, Time: time.Now(), Liters: litersUsed, Km: km})
+	return nil
+}
+
+// finite reports whether every value is a number other than ±Inf. NaN gets
+// past the range checks and can't be encoded as JSON, so it never reaches a truck.
+func finite(values ...float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// setFuelLocked replaces the fuel fields of an existing truck. Callers must hold the write lock.
+func (tm *truckManager) setFuelLocked(truckID string, capacity, level float64) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.FuelCapacity = capacity
+	updated.FuelLevel = level
+	updated.Version++
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+	old := *truck
+	tm.trucks[truckID].store(&updated)
+	tm.emit(FuelChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("fuel", truckID, &old, &updated)
+	return nil
+}
+
+// FuelRecords returns a truck's refuels and trips in [from, to), oldest first
+func (tm *truckManager) FuelRecords(truckID string, from, to time.Time) ([]FuelRecord, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if _, exist := tm.trucks[truckID]; !exist {
+		return nil, ErrTruckNotFound
+	}
+	var records []FuelRecord
+	for _, rec := range tm.fuel[truckID] {
+		if !rec.Time.Before(from) && rec.Time.Before(to) {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// FuelReport computes fuel use and efficiency in [from, to) for every truck
+// with records in the period, and for the fleet as a whole. Records are
+// kept in memory only and are dropped with their truck.
+func (tm *truckManager) FuelReport(from, to time.Time) FuelReport {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	report := FuelReport{From: from, To: to}
+	for truckID, records := range tm.fuel {
+		truck := TruckFuelReport{TruckID: truckID}
+		found := false
+		for _, rec := range records {
+			if rec.Time.Before(from) || !rec.Time.Before(to) {
+				continue
+			}
+			found = true
+			truck.add(rec)
+			report.Fleet.add(rec)
+		}
+		if found {
+			truck.finish()
+			report.Trucks = append(report.Trucks, truck)
+		}
+	}
+	report.Fleet.finish()
+	sort.Slice(report.Trucks, func(i, j int) bool { return report.Trucks[i].TruckID < report.Trucks[j].TruckID })
+	return report
+}
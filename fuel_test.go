@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRecordRefuelAndTrip(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetFuelTank("1", 400)
+
+	if err := manager.RecordRefuel("1", 300, 450); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.RecordTrip("1", 600, 200); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.FuelCapacity != 400 || truck.FuelLevel != 100 {
+		t.Errorf("Expected 100 of 400 liters, got %g of %g", truck.FuelLevel, truck.FuelCapacity)
+	}
+	if truck.Version != 4 {
+		t.Errorf("Expected version 4, got %d", truck.Version)
+	}
+}
+
+func TestFuelErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetFuelTank("1", 100)
+	manager.RecordRefuel("1", 80, 100)
+
+	if err := manager.RecordRefuel("1", 30, 40); !errors.Is(err, ErrFuelTankOverflow) {
+		t.Errorf("Expected ErrFuelTankOverflow, got %v", err)
+	}
+	if err := manager.RecordTrip("1", 500, 90); !errors.Is(err, ErrInsufficientFuel) {
+		t.Errorf("Expected ErrInsufficientFuel, got %v", err)
+	}
+	if err := manager.RecordRefuel("1", -5, 0); err != ErrInvalidFuel {
+		t.Errorf("Expected ErrInvalidFuel, got %v", err)
+	}
+	if err := manager.SetFuelTank("1", -1); err != ErrInvalidFuel {
+		t.Errorf("Expected ErrInvalidFuel, got %v", err)
+	}
+	if err := manager.RecordTrip("x", 1, 1); err != ErrTruckNotFound {
+		t.Errorf("Expected ErrTruckNotFound, got %v", err)
+	}
+
+	// Rejected records leave the level alone
+	truck, _ := manager.GetTruck("1")
+	if truck.FuelLevel != 80 {
+		t.Errorf("Expected 80 liters, got %g", truck.FuelLevel)
+	}
+}
+
+func TestFuelRejectsNonFinite(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetFuelTank("1", 100)
+
+	nan, inf := math.NaN(), math.Inf(1)
+	if err := manager.SetFuelTank("1", inf); !errors.Is(err, ErrInvalidFuel) {
+		t.Errorf("Expected ErrInvalidFuel for an infinite tank, got %v", err)
+	}
+	if err := manager.RecordRefuel("1", nan, 10); !errors.Is(err, ErrInvalidFuel) {
+		t.Errorf("Expected ErrInvalidFuel for NaN liters, got %v", err)
+	}
+	if err := manager.RecordRefuel("1", 10, inf); !errors.Is(err, ErrInvalidFuel) {
+		t.Errorf("Expected ErrInvalidFuel for an infinite cost, got %v", err)
+	}
+	if err := manager.RecordTrip("1", nan, 0); !errors.Is(err, ErrInvalidFuel) {
+		t.Errorf("Expected ErrInvalidFuel for NaN km, got %v", err)
+	}
+
+	// The fleet still exports
+	var buf bytes.Buffer
+	if err := manager.ExportFleet(&buf, FormatJSON); err != nil {
+		t.Errorf("Expected export to succeed, got %v", err)
+	}
+}
+
+func TestSetFuelTankLowersLevel(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetFuelTank("1", 100)
+	manager.RecordRefuel("1", 90, 100)
+	manager.SetFuelTank("1", 50)
+
+	truck, _ := manager.GetTruck("1")
+	if truck.FuelLevel != 50 {
+		t.Errorf("Expected level lowered to 50, got %g", truck.FuelLevel)
+	}
+}
+
+func TestFuelReport(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("a", 100)
+	manager.AddTruck("b", 100)
+	manager.AddTruck("c", 100)
+	for _, id := range []string{"a", "b", "c"} {
+		manager.SetFuelTank(id, 500)
+	}
+
+	from := time.Now()
+	manager.RecordRefuel("a", 200, 300)
+	manager.RecordTrip("a", 800, 200)
+	manager.RecordRefuel("b", 100, 160)
+	manager.RecordTrip("b", 200, 100)
+	to := time.Now().Add(time.Nanosecond)
+
+	report := manager.FuelReport(from, to)
+	if len(report.Trucks) != 2 || report.Trucks[0].TruckID != "a" || report.Trucks[1].TruckID != "b" {
+		t.Fatalf("Expected reports for a and b, got %+v", report.Trucks)
+	}
+	a := report.Trucks[0]
+	if a.Km != 800 || a.LitersUsed != 200 || a.KmPerLiter != 4 || a.CostPerLiter != 1.5 {
+		t.Errorf("Expected 800 km on 200 liters at 1.5 per liter, got %+v", a)
+	}
+	fleet := report.Fleet
+	if fleet.Km != 1000 || fleet.LitersUsed != 300 || fleet.Cost != 460 || fleet.LitersRefueled != 300 {
+		t.Errorf("Expected fleet totals of 1000 km, 300 liters used and refueled, cost 460, got %+v", fleet)
+	}
+
+	// Records outside the range are left out
+	later := manager.FuelReport(to, to.Add(time.Hour))
+	if len(later.Trucks) != 0 || later.Fleet.KmPerLiter != 0 {
+		t.Errorf("Expected an empty report, got %+v", later)
+	}
+}
+
+func TestFuelRecordsDroppedWithTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetFuelTank("1", 100)
+	manager.RecordRefuel("1", 50, 60)
+
+	records, _ := manager.FuelRecords("1", time.Time{}, time.Now().Add(time.Hour))
+	if len(records) != 1 || !records[0].Refuel || records[0].Liters != 50 {
+		t.Errorf("Expected one refuel of 50 liters, got %+v", records)
+	}
+
+	manager.RemoveTruck("1")
+	manager.AddTruck("1", 100)
+	records, _ = manager.FuelRecords("1", time.Time{}, time.Now().Add(time.Hour))
+	if len(records) != 0 {
+		t.Errorf("Expected no records for a new truck, got %+v", records)
+	}
+}
@@ -0,0 +1,573 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: fleet/v1/fleet.proto
+
+package fleetv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Truck struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// capacity is the most cargo the truck can carry.
+	Capacity int64 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	// current_load is the cargo on board.
+	CurrentLoad   int64 `protobuf:"varint,4,opt,name=current_load,json=currentLoad,proto3" json:"current_load,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Truck) Reset() {
+	*x = Truck{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Truck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Truck) ProtoMessage() {}
+
+func (x *Truck) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Truck.ProtoReflect.Descriptor instead.
+func (*Truck) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Truck) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Truck) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Truck) GetCurrentLoad() int64 {
+	if x != nil {
+		return x.CurrentLoad
+	}
+	return 0
+}
+
+type AddTruckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Capacity      int64                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTruckRequest) Reset() {
+	*x = AddTruckRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTruckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTruckRequest) ProtoMessage() {}
+
+func (x *AddTruckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTruckRequest.ProtoReflect.Descriptor instead.
+func (*AddTruckRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddTruckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AddTruckRequest) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type AddTruckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Truck         *Truck                 `protobuf:"bytes,1,opt,name=truck,proto3" json:"truck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddTruckResponse) Reset() {
+	*x = AddTruckResponse{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddTruckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddTruckResponse) ProtoMessage() {}
+
+func (x *AddTruckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddTruckResponse.ProtoReflect.Descriptor instead.
+func (*AddTruckResponse) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddTruckResponse) GetTruck() *Truck {
+	if x != nil {
+		return x.Truck
+	}
+	return nil
+}
+
+type GetTruckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTruckRequest) Reset() {
+	*x = GetTruckRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTruckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTruckRequest) ProtoMessage() {}
+
+func (x *GetTruckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTruckRequest.ProtoReflect.Descriptor instead.
+func (*GetTruckRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTruckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RemoveTruckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTruckRequest) Reset() {
+	*x = RemoveTruckRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTruckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTruckRequest) ProtoMessage() {}
+
+func (x *RemoveTruckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTruckRequest.ProtoReflect.Descriptor instead.
+func (*RemoveTruckRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RemoveTruckRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RemoveTruckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveTruckResponse) Reset() {
+	*x = RemoveTruckResponse{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveTruckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveTruckResponse) ProtoMessage() {}
+
+func (x *RemoveTruckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveTruckResponse.ProtoReflect.Descriptor instead.
+func (*RemoveTruckResponse) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{5}
+}
+
+type UpdateCargoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Capacity      int64                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCargoRequest) Reset() {
+	*x = UpdateCargoRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCargoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCargoRequest) ProtoMessage() {}
+
+func (x *UpdateCargoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCargoRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCargoRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateCargoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCargoRequest) GetCapacity() int64 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type MoveCargoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// amount must be positive.
+	Amount        int64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCargoRequest) Reset() {
+	*x = MoveCargoRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCargoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCargoRequest) ProtoMessage() {}
+
+func (x *MoveCargoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCargoRequest.ProtoReflect.Descriptor instead.
+func (*MoveCargoRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MoveCargoRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveCargoRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type ListTrucksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// offset is the number of trucks to skip, in ID order.
+	Offset int32 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// page_size is how many trucks the server reads per lock acquisition;
+	// 0 selects the server default.
+	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrucksRequest) Reset() {
+	*x = ListTrucksRequest{}
+	mi := &file_fleet_v1_fleet_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrucksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrucksRequest) ProtoMessage() {}
+
+func (x *ListTrucksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fleet_v1_fleet_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrucksRequest.ProtoReflect.Descriptor instead.
+func (*ListTrucksRequest) Descriptor() ([]byte, []int) {
+	return file_fleet_v1_fleet_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTrucksRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListTrucksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+var File_fleet_v1_fleet_proto protoreflect.FileDescriptor
+
+const file_fleet_v1_fleet_proto_rawDesc = "" +
+	"\n" +
+	"\x14fleet/v1/fleet.proto\x12\bfleet.v1\"c\n" +
+	"\x05Truck\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x03R\bcapacity\x12!\n" +
+	"\fcurrent_load\x18\x04 \x01(\x03R\vcurrentLoadJ\x04\b\x02\x10\x03R\x05cargo\"J\n" +
+	"\x0fAddTruckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x03R\bcapacityJ\x04\b\x02\x10\x03R\x05cargo\"9\n" +
+	"\x10AddTruckResponse\x12%\n" +
+	"\x05truck\x18\x01 \x01(\v2\x0f.fleet.v1.TruckR\x05truck\"!\n" +
+	"\x0fGetTruckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12RemoveTruckRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x15\n" +
+	"\x13RemoveTruckResponse\"M\n" +
+	"\x12UpdateCargoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x03R\bcapacityJ\x04\b\x02\x10\x03R\x05cargo\":\n" +
+	"\x10MoveCargoRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"H\n" +
+	"\x11ListTrucksRequest\x12\x16\n" +
+	"\x06offset\x18\x01 \x01(\x05R\x06offset\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize2\xc7\x03\n" +
+	"\fFleetService\x12A\n" +
+	"\bAddTruck\x12\x19.fleet.v1.AddTruckRequest\x1a\x1a.fleet.v1.AddTruckResponse\x126\n" +
+	"\bGetTruck\x12\x19.fleet.v1.GetTruckRequest\x1a\x0f.fleet.v1.Truck\x12J\n" +
+	"\vRemoveTruck\x12\x1c.fleet.v1.RemoveTruckRequest\x1a\x1d.fleet.v1.RemoveTruckResponse\x12<\n" +
+	"\vUpdateCargo\x12\x1c.fleet.v1.UpdateCargoRequest\x1a\x0f.fleet.v1.Truck\x128\n" +
+	"\tLoadCargo\x12\x1a.fleet.v1.MoveCargoRequest\x1a\x0f.fleet.v1.Truck\x12:\n" +
+	"\vUnloadCargo\x12\x1a.fleet.v1.MoveCargoRequest\x1a\x0f.fleet.v1.Truck\x12<\n" +
+	"\n" +
+	"ListTrucks\x12\x1b.fleet.v1.ListTrucksRequest\x1a\x0f.fleet.v1.Truck0\x01B\x1fZ\x1dCapstone/gen/fleet/v1;fleetv1b\x06proto3"
+
+var (
+	file_fleet_v1_fleet_proto_rawDescOnce sync.Once
+	file_fleet_v1_fleet_proto_rawDescData []byte
+)
+
+func file_fleet_v1_fleet_proto_rawDescGZIP() []byte {
+	file_fleet_v1_fleet_proto_rawDescOnce.Do(func() {
+		file_fleet_v1_fleet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_fleet_v1_fleet_proto_rawDesc), len(file_fleet_v1_fleet_proto_rawDesc)))
+	})
+	return file_fleet_v1_fleet_proto_rawDescData
+}
+
+var file_fleet_v1_fleet_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_fleet_v1_fleet_proto_goTypes = []any{
+	(*Truck)(nil),               // 0: fleet.v1.Truck
+	(*AddTruckRequest)(nil),     // 1: fleet.v1.AddTruckRequest
+	(*AddTruckResponse)(nil),    // 2: fleet.v1.AddTruckResponse
+	(*GetTruckRequest)(nil),     // 3: fleet.v1.GetTruckRequest
+	(*RemoveTruckRequest)(nil),  // 4: fleet.v1.RemoveTruckRequest
+	(*RemoveTruckResponse)(nil), // 5: fleet.v1.RemoveTruckResponse
+	(*UpdateCargoRequest)(nil),  // 6: fleet.v1.UpdateCargoRequest
+	(*MoveCargoRequest)(nil),    // 7: fleet.v1.MoveCargoRequest
+	(*ListTrucksRequest)(nil),   // 8: fleet.v1.ListTrucksRequest
+}
+var file_fleet_v1_fleet_proto_depIdxs = []int32{
+	0, // 0: fleet.v1.AddTruckResponse.truck:type_name -> fleet.v1.Truck
+	1, // 1: fleet.v1.FleetService.AddTruck:input_type -> fleet.v1.AddTruckRequest
+	3, // 2: fleet.v1.FleetService.GetTruck:input_type -> fleet.v1.GetTruckRequest
+	4, // 3: fleet.v1.FleetService.RemoveTruck:input_type -> fleet.v1.RemoveTruckRequest
+	6, // 4: fleet.v1.FleetService.UpdateCargo:input_type -> fleet.v1.UpdateCargoRequest
+	7, // 5: fleet.v1.FleetService.LoadCargo:input_type -> fleet.v1.MoveCargoRequest
+	7, // 6: fleet.v1.FleetService.UnloadCargo:input_type -> fleet.v1.MoveCargoRequest
+	8, // 7: fleet.v1.FleetService.ListTrucks:input_type -> fleet.v1.ListTrucksRequest
+	2, // 8: fleet.v1.FleetService.AddTruck:output_type -> fleet.v1.AddTruckResponse
+	0, // 9: fleet.v1.FleetService.GetTruck:output_type -> fleet.v1.Truck
+	5, // 10: fleet.v1.FleetService.RemoveTruck:output_type -> fleet.v1.RemoveTruckResponse
+	0, // 11: fleet.v1.FleetService.UpdateCargo:output_type -> fleet.v1.Truck
+	0, // 12: fleet.v1.FleetService.LoadCargo:output_type -> fleet.v1.Truck
+	0, // 13: fleet.v1.FleetService.UnloadCargo:output_type -> fleet.v1.Truck
+	0, // 14: fleet.v1.FleetService.ListTrucks:output_type -> fleet.v1.Truck
+	8, // [8:15] is the sub-list for method output_type
+	1, // [1:8] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_fleet_v1_fleet_proto_init() }
+func file_fleet_v1_fleet_proto_init() {
+	if File_fleet_v1_fleet_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fleet_v1_fleet_proto_rawDesc), len(file_fleet_v1_fleet_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_fleet_v1_fleet_proto_goTypes,
+		DependencyIndexes: file_fleet_v1_fleet_proto_depIdxs,
+		MessageInfos:      file_fleet_v1_fleet_proto_msgTypes,
+	}.Build()
+	File_fleet_v1_fleet_proto = out.File
+	file_fleet_v1_fleet_proto_goTypes = nil
+	file_fleet_v1_fleet_proto_depIdxs = nil
+}
@@ -0,0 +1,387 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: fleet/v1/fleet.proto
+
+package fleetv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FleetService_AddTruck_FullMethodName    = "/fleet.v1.FleetService/AddTruck"
+	FleetService_GetTruck_FullMethodName    = "/fleet.v1.FleetService/GetTruck"
+	FleetService_RemoveTruck_FullMethodName = "/fleet.v1.FleetService/RemoveTruck"
+	FleetService_UpdateCargo_FullMethodName = "/fleet.v1.FleetService/UpdateCargo"
+	FleetService_LoadCargo_FullMethodName   = "/fleet.v1.FleetService/LoadCargo"
+	FleetService_UnloadCargo_FullMethodName = "/fleet.v1.FleetService/UnloadCargo"
+	FleetService_ListTrucks_FullMethodName  = "/fleet.v1.FleetService/ListTrucks"
+)
+
+// FleetServiceClient is the client API for FleetService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FleetService exposes FleetManager to other services.
+//
+// Manager errors map to status codes as follows:
+//
+//	ErrEmptyID, ErrInvalidCargo, ErrInvalidPage -> INVALID_ARGUMENT
+//	ErrTruckNotFound                            -> NOT_FOUND
+//	ErrTruckExist                               -> ALREADY_EXISTS
+//	ErrCapacityExceeded, ErrInsufficientCargo,
+//	ErrRouteWeightLimit                         -> FAILED_PRECONDITION
+//	ErrVersionConflict                          -> ABORTED
+//	ErrFleetLimitReached                        -> RESOURCE_EXHAUSTED
+type FleetServiceClient interface {
+	AddTruck(ctx context.Context, in *AddTruckRequest, opts ...grpc.CallOption) (*AddTruckResponse, error)
+	GetTruck(ctx context.Context, in *GetTruckRequest, opts ...grpc.CallOption) (*Truck, error)
+	RemoveTruck(ctx context.Context, in *RemoveTruckRequest, opts ...grpc.CallOption) (*RemoveTruckResponse, error)
+	// UpdateCargo sets the most cargo a truck can carry.
+	UpdateCargo(ctx context.Context, in *UpdateCargoRequest, opts ...grpc.CallOption) (*Truck, error)
+	// LoadCargo and UnloadCargo move cargo on and off a truck, keeping
+	// current_load between 0 and capacity.
+	LoadCargo(ctx context.Context, in *MoveCargoRequest, opts ...grpc.CallOption) (*Truck, error)
+	UnloadCargo(ctx context.Context, in *MoveCargoRequest, opts ...grpc.CallOption) (*Truck, error)
+	// ListTrucks streams the fleet in ID order, one page of the manager's
+	// ListTrucks at a time, starting at offset.
+	ListTrucks(ctx context.Context, in *ListTrucksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Truck], error)
+}
+
+type fleetServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFleetServiceClient(cc grpc.ClientConnInterface) FleetServiceClient {
+	return &fleetServiceClient{cc}
+}
+
+func (c *fleetServiceClient) AddTruck(ctx context.Context, in *AddTruckRequest, opts ...grpc.CallOption) (*AddTruckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTruckResponse)
+	err := c.cc.Invoke(ctx, FleetService_AddTruck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) GetTruck(ctx context.Context, in *GetTruckRequest, opts ...grpc.CallOption) (*Truck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Truck)
+	err := c.cc.Invoke(ctx, FleetService_GetTruck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) RemoveTruck(ctx context.Context, in *RemoveTruckRequest, opts ...grpc.CallOption) (*RemoveTruckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveTruckResponse)
+	err := c.cc.Invoke(ctx, FleetService_RemoveTruck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) UpdateCargo(ctx context.Context, in *UpdateCargoRequest, opts ...grpc.CallOption) (*Truck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Truck)
+	err := c.cc.Invoke(ctx, FleetService_UpdateCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) LoadCargo(ctx context.Context, in *MoveCargoRequest, opts ...grpc.CallOption) (*Truck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Truck)
+	err := c.cc.Invoke(ctx, FleetService_LoadCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) UnloadCargo(ctx context.Context, in *MoveCargoRequest, opts ...grpc.CallOption) (*Truck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Truck)
+	err := c.cc.Invoke(ctx, FleetService_UnloadCargo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fleetServiceClient) ListTrucks(ctx context.Context, in *ListTrucksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Truck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FleetService_ServiceDesc.Streams[0], FleetService_ListTrucks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListTrucksRequest, Truck]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FleetService_ListTrucksClient = grpc.ServerStreamingClient[Truck]
+
+// FleetServiceServer is the server API for FleetService service.
+// All implementations must embed UnimplementedFleetServiceServer
+// for forward compatibility.
+//
+// FleetService exposes FleetManager to other services.
+//
+// Manager errors map to status codes as follows:
+//
+//	ErrEmptyID, ErrInvalidCargo, ErrInvalidPage -> INVALID_ARGUMENT
+//	ErrTruckNotFound                            -> NOT_FOUND
+//	ErrTruckExist                               -> ALREADY_EXISTS
+//	ErrCapacityExceeded, ErrInsufficientCargo,
+//	ErrRouteWeightLimit                         -> FAILED_PRECONDITION
+//	ErrVersionConflict                          -> ABORTED
+//	ErrFleetLimitReached                        -> RESOURCE_EXHAUSTED
+type FleetServiceServer interface {
+	AddTruck(context.Context, *AddTruckRequest) (*AddTruckResponse, error)
+	GetTruck(context.Context, *GetTruckRequest) (*Truck, error)
+	RemoveTruck(context.Context, *RemoveTruckRequest) (*RemoveTruckResponse, error)
+	// UpdateCargo sets the most cargo a truck can carry.
+	UpdateCargo(context.Context, *UpdateCargoRequest) (*Truck, error)
+	// LoadCargo and UnloadCargo move cargo on and off a truck, keeping
+	// current_load between 0 and capacity.
+	LoadCargo(context.Context, *MoveCargoRequest) (*Truck, error)
+	UnloadCargo(context.Context, *MoveCargoRequest) (*Truck, error)
+	// ListTrucks streams the fleet in ID order, one page of the manager's
+	// ListTrucks at a time, starting at offset.
+	ListTrucks(*ListTrucksRequest, grpc.ServerStreamingServer[Truck]) error
+	mustEmbedUnimplementedFleetServiceServer()
+}
+
+// UnimplementedFleetServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFleetServiceServer struct{}
+
+func (UnimplementedFleetServiceServer) AddTruck(context.Context, *AddTruckRequest) (*AddTruckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddTruck not implemented")
+}
+func (UnimplementedFleetServiceServer) GetTruck(context.Context, *GetTruckRequest) (*Truck, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTruck not implemented")
+}
+func (UnimplementedFleetServiceServer) RemoveTruck(context.Context, *RemoveTruckRequest) (*RemoveTruckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RemoveTruck not implemented")
+}
+func (UnimplementedFleetServiceServer) UpdateCargo(context.Context, *UpdateCargoRequest) (*Truck, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCargo not implemented")
+}
+func (UnimplementedFleetServiceServer) LoadCargo(context.Context, *MoveCargoRequest) (*Truck, error) {
+	return nil, status.Error(codes.Unimplemented, "method LoadCargo not implemented")
+}
+func (UnimplementedFleetServiceServer) UnloadCargo(context.Context, *MoveCargoRequest) (*Truck, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnloadCargo not implemented")
+}
+func (UnimplementedFleetServiceServer) ListTrucks(*ListTrucksRequest, grpc.ServerStreamingServer[Truck]) error {
+	return status.Error(codes.Unimplemented, "method ListTrucks not implemented")
+}
+func (UnimplementedFleetServiceServer) mustEmbedUnimplementedFleetServiceServer() {}
+func (UnimplementedFleetServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeFleetServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FleetServiceServer will
+// result in compilation errors.
+type UnsafeFleetServiceServer interface {
+	mustEmbedUnimplementedFleetServiceServer()
+}
+
+func RegisterFleetServiceServer(s grpc.ServiceRegistrar, srv FleetServiceServer) {
+	// If the following call panics, it indicates UnimplementedFleetServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FleetService_ServiceDesc, srv)
+}
+
+func _FleetService_AddTruck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTruckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).AddTruck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_AddTruck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).AddTruck(ctx, req.(*AddTruckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_GetTruck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTruckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).GetTruck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_GetTruck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).GetTruck(ctx, req.(*GetTruckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_RemoveTruck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveTruckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).RemoveTruck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_RemoveTruck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).RemoveTruck(ctx, req.(*RemoveTruckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_UpdateCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).UpdateCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_UpdateCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).UpdateCargo(ctx, req.(*UpdateCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_LoadCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).LoadCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_LoadCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).LoadCargo(ctx, req.(*MoveCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_UnloadCargo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCargoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FleetServiceServer).UnloadCargo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FleetService_UnloadCargo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FleetServiceServer).UnloadCargo(ctx, req.(*MoveCargoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FleetService_ListTrucks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListTrucksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FleetServiceServer).ListTrucks(m, &grpc.GenericServerStream[ListTrucksRequest, Truck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FleetService_ListTrucksServer = grpc.ServerStreamingServer[Truck]
+
+// FleetService_ServiceDesc is the grpc.ServiceDesc for FleetService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FleetService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fleet.v1.FleetService",
+	HandlerType: (*FleetServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddTruck",
+			Handler:    _FleetService_AddTruck_Handler,
+		},
+		{
+			MethodName: "GetTruck",
+			Handler:    _FleetService_GetTruck_Handler,
+		},
+		{
+			MethodName: "RemoveTruck",
+			Handler:    _FleetService_RemoveTruck_Handler,
+		},
+		{
+			MethodName: "UpdateCargo",
+			Handler:    _FleetService_UpdateCargo_Handler,
+		},
+		{
+			MethodName: "LoadCargo",
+			Handler:    _FleetService_LoadCargo_Handler,
+		},
+		{
+			MethodName: "UnloadCargo",
+			Handler:    _FleetService_UnloadCargo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListTrucks",
+			Handler:       _FleetService_ListTrucks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "fleet/v1/fleet.proto",
+}
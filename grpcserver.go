@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	fleetv1 "Capstone/gen/fleet/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate buf generate
+
+// Page sizes the gRPC ListTrucks stream reads the fleet in
+const (
+	defaultStreamPageSize = 100
+	maxStreamPageSize     = 1000
+)
+
+// FleetServer implements the gRPC FleetService by wrapping a FleetManager.
+// Calls honour the request deadline when the manager is also a
+// ContextFleetManager; otherwise the deadline is checked before each call.
+type FleetServer struct {
+	fleetv1.UnimplementedFleetServiceServer
+	manager ContextFleetManager
+}
+
+// NewFleetServer creates a server for manager. Register it with
+// fleetv1.RegisterFleetServiceServer.
+func NewFleetServer(manager FleetManager) *FleetServer {
+	cm, ok := manager.(ContextFleetManager)
+	if !ok {
+		cm = contextChecked{manager}
+	}
+	return &FleetServer{manager: cm}
+}
+
+// AddTruck adds an empty truck
+func (s *FleetServer) AddTruck(ctx context.Context, req *fleetv1.AddTruckRequest) (*fleetv1.AddTruckResponse, error) {
+	capacity, err := toInt(req.GetCapacity())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.manager.AddTruckContext(ctx, req.GetId(), capacity); err != nil {
+		return nil, grpcError(err)
+	}
+	truck, err := s.get(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &fleetv1.AddTruckResponse{Truck: truck}, nil
+}
+
+// GetTruck returns one truck
+func (s *FleetServer) GetTruck(ctx context.Context, req *fleetv1.GetTruckRequest) (*fleetv1.Truck, error) {
+	return s.get(ctx, req.GetId())
+}
+
+// RemoveTruck removes a truck
+func (s *FleetServer) RemoveTruck(ctx context.Context, req *fleetv1.RemoveTruckRequest) (*fleetv1.RemoveTruckResponse, error) {
+	if err := s.manager.RemoveTruckContext(ctx, req.GetId()); err != nil {
+		return nil, grpcError(err)
+	}
+	return &fleetv1.RemoveTruckResponse{}, nil
+}
+
+// UpdateCargo sets the capacity of a truck and returns the updated truck
+func (s *FleetServer) UpdateCargo(ctx context.Context, req *fleetv1.UpdateCargoRequest) (*fleetv1.Truck, error) {
+	capacity, err := toInt(req.GetCapacity())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.manager.UpdateTruckCargoContext(ctx, req.GetId(), capacity); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.get(ctx, req.GetId())
+}
+
+// LoadCargo puts cargo on a truck and returns the updated truck
+func (s *FleetServer) LoadCargo(ctx context.Context, req *fleetv1.MoveCargoRequest) (*fleetv1.Truck, error) {
+	amount, err := toInt(req.GetAmount())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.manager.LoadCargoContext(ctx, req.GetId(), amount); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.get(ctx, req.GetId())
+}
+
+// UnloadCargo takes cargo off a truck and returns the updated truck
+func (s *FleetServer) UnloadCargo(ctx context.Context, req *fleetv1.MoveCargoRequest) (*fleetv1.Truck, error) {
+	amount, err := toInt(req.GetAmount())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if err := s.manager.UnloadCargoContext(ctx, req.GetId(), amount); err != nil {
+		return nil, grpcError(err)
+	}
+	return s.get(ctx, req.GetId())
+}
+
+// ListTrucks streams the fleet in ID order one page at a time, so a large
+// fleet is never copied whole and the lock is released between pages.
+// Trucks added or removed mid-stream may shift later pages.
+func (s *FleetServer) ListTrucks(req *fleetv1.ListTrucksRequest, stream grpc.ServerStreamingServer[fleetv1.Truck]) error {
+	size := int(req.GetPageSize())
+	switch {
+	case size == 0:
+		size = defaultStreamPageSize
+	case size > maxStreamPageSize:
+		size = maxStreamPageSize
+	}
+
+	ctx := stream.Context()
+	for offset := int(req.GetOffset()); ; {
+		page, err := s.manager.ListTrucksContext(ctx, offset, size)
+		if err != nil {
+			return grpcError(err)
+		}
+		for _, t := range page {
+			if err := stream.Send(truckToProto(t)); err != nil {
+				return err
+			}
+		}
+		if len(page) < size {
+			return nil
+		}
+		offset += len(page)
+	}
+}
+
+// get fetches a truck as its protobuf message
+func (s *FleetServer) get(ctx context.Context, id string) (*fleetv1.Truck, error) {
+	truck, err := s.manager.GetTruckContext(ctx, id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return truckToProto(truck), nil
+}
+
+// truckToProto converts a truck to its protobuf message
+func truckToProto(t Truck) *fleetv1.Truck {
+	return &fleetv1.Truck{Id: t.ID, Capacity: int64(t.Capacity), CurrentLoad: int64(t.CurrentLoad)}
+}
+
+// toInt converts a cargo value from the wire, rejecting values int can't hold
+func toInt(v int64) (int, error) {
+	if int64(int(v)) != v {
+		return 0, ErrInvalidCargo
+	}
+	return int(v), nil
+}
+
+// grpcError maps a manager error to the status code fleet.proto documents
+func grpcError(err error) error {
+	var code codes.Code
+	switch {
+	case errors.Is(err, ErrEmptyID), errors.Is(err, ErrInvalidCargo), errors.Is(err, ErrInvalidPage):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrTruckNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrTruckExist):
+		code = codes.AlreadyExists
+	case errors.Is(err, ErrCapacityExceeded), errors.Is(err, ErrInsufficientCargo), errors.Is(err, ErrRouteWeightLimit):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrVersionConflict):
+		code = codes.Aborted
+	case errors.Is(err, ErrFleetLimitReached):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Unknown
+	}
+	return status.Error(code, err.Error())
+}
+
+// contextChecked adapts a FleetManager without context support by checking
+// the context before each call
+type contextChecked struct {
+	FleetManager
+}
+
+func (m contextChecked) AddTruckContext(ctx context.Context, id string, capacity int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.AddTruck(id, capacity)
+}
+
+func (m contextChecked) GetTruckContext(ctx context.Context, id string) (Truck, error) {
+	if err := ctx.Err(); err != nil {
+		return Truck{}, err
+	}
+	return m.GetTruck(id)
+}
+
+func (m contextChecked) RemoveTruckContext(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.RemoveTruck(id)
+}
+
+func (m contextChecked) UpdateTruckCargoContext(ctx context.Context, id string, capacity int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.UpdateTruckCargo(id, capacity)
+}
+
+func (m contextChecked) LoadCargoContext(ctx context.Context, id string, amount int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.LoadCargo(id, amount)
+}
+
+func (m contextChecked) UnloadCargoContext(ctx context.Context, id string, amount int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.UnloadCargo(id, amount)
+}
+
+func (m contextChecked) ListTrucksContext(ctx context.Context, offset, limit int) ([]Truck, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.ListTrucks(offset, limit)
+}
+
+// runServeCommand serves the fleet over gRPC until interrupted
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":50051", "address to listen on")
+	data := fs.String("data", "", "BoltDB file to keep the fleet in (empty keeps it in memory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := []Option{WithPurgeEvery(ctx, time.Hour)}
+	if *data != "" {
+		storage, err := NewBoltStorage(*data)
+		if err != nil {
+			return err
+		}
+		defer storage.Close()
+		opts = append(opts, WithStorage(storage))
+	}
+	manager, err := OpenTruckManager(opts...)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer()
+	fleetv1.RegisterFleetServiceServer(server, NewFleetServer(manager))
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving FleetService on %s\n", lis.Addr())
+	return server.Serve(lis)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	fleetv1 "Capstone/gen/fleet/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newFleetClient serves manager over an in-memory connection and returns a generated client for it
+func newFleetClient(t *testing.T, manager FleetManager) fleetv1.FleetServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	fleetv1.RegisterFleetServiceServer(server, NewFleetServer(manager))
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return fleetv1.NewFleetServiceClient(conn)
+}
+
+func TestFleetServer(t *testing.T) {
+	client := newFleetClient(t, NewFleetManager())
+	ctx := context.Background()
+
+	added, err := client.AddTruck(ctx, &fleetv1.AddTruckRequest{Id: "1", Capacity: 100})
+	if err != nil || added.GetTruck().GetCapacity() != 100 {
+		t.Fatalf("Expected truck 1 with capacity 100, got %v, %v", added, err)
+	}
+	if _, err := client.UpdateCargo(ctx, &fleetv1.UpdateCargoRequest{Id: "1", Capacity: 200}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	loaded, err := client.LoadCargo(ctx, &fleetv1.MoveCargoRequest{Id: "1", Amount: 150})
+	if err != nil || loaded.GetCurrentLoad() != 150 {
+		t.Errorf("Expected load 150, got %v, %v", loaded, err)
+	}
+	unloaded, err := client.UnloadCargo(ctx, &fleetv1.MoveCargoRequest{Id: "1", Amount: 50})
+	if err != nil || unloaded.GetCurrentLoad() != 100 {
+		t.Errorf("Expected load 100, got %v, %v", unloaded, err)
+	}
+	truck, err := client.GetTruck(ctx, &fleetv1.GetTruckRequest{Id: "1"})
+	if err != nil || truck.GetCapacity() != 200 || truck.GetCurrentLoad() != 100 {
+		t.Errorf("Expected capacity 200 and load 100, got %v, %v", truck, err)
+	}
+	if _, err := client.RemoveTruck(ctx, &fleetv1.RemoveTruckRequest{Id: "1"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestFleetServerErrorCodes(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.CreateRoute(Route{ID: "r1", Origin: "Nairobi", Destination: "Mombasa", WeightLimit: 10})
+	manager.AssignRoute("2", "r1")
+	client := newFleetClient(t, manager)
+	// The stub's UpdateTruckCargo always loses a compare-and-swap race
+	conflicting := newFleetClient(t, conflictingFleet{manager})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"empty id", func() error { _, err := client.GetTruck(ctx, &fleetv1.GetTruckRequest{}); return err }, codes.InvalidArgument},
+		{"not found", func() error { _, err := client.GetTruck(ctx, &fleetv1.GetTruckRequest{Id: "missing"}); return err }, codes.NotFound},
+		{"exists", func() error {
+			_, err := client.AddTruck(ctx, &fleetv1.AddTruckRequest{Id: "1", Capacity: 100})
+			return err
+		}, codes.AlreadyExists},
+		{"over capacity", func() error {
+			_, err := client.LoadCargo(ctx, &fleetv1.MoveCargoRequest{Id: "1", Amount: 500})
+			return err
+		}, codes.FailedPrecondition},
+		{"route weight limit", func() error {
+			_, err := client.LoadCargo(ctx, &fleetv1.MoveCargoRequest{Id: "2", Amount: 50})
+			return err
+		}, codes.FailedPrecondition},
+		{"version conflict", func() error {
+			_, err := conflicting.UpdateCargo(ctx, &fleetv1.UpdateCargoRequest{Id: "1", Capacity: 200})
+			return err
+		}, codes.Aborted},
+		{"negative amount", func() error {
+			_, err := client.LoadCargo(ctx, &fleetv1.MoveCargoRequest{Id: "1", Amount: -1})
+			return err
+		}, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		if code := status.Code(tt.call()); code != tt.code {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.code, code)
+		}
+	}
+}
+
+// conflictingFleet is a FleetManager whose capacity updates always hit a version conflict
+type conflictingFleet struct {
+	FleetManager
+}
+
+func (conflictingFleet) UpdateTruckCargo(string, int) error {
+	return ErrVersionConflict
+}
+
+func TestFleetServerListTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	for _, id := range []string{"c", "a", "e", "b", "d"} {
+		manager.AddTruck(id, 100)
+	}
+	// BlueGreen has no context methods, so this also covers the adapter
+	client := newFleetClient(t, NewBlueGreen(manager, NewFleetManager()))
+
+	stream, err := client.ListTrucks(context.Background(), &fleetv1.ListTrucksRequest{Offset: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var ids []string
+	for {
+		truck, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ids = append(ids, truck.GetId())
+	}
+	if len(ids) != 4 || ids[0] != "b" || ids[3] != "e" {
+		t.Errorf("Expected trucks b to e in ID order, got %v", ids)
+	}
+}
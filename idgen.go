@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrIDCollision is returned when the ID generator keeps producing IDs that are already taken
+var ErrIDCollision = errors.New("could not generate a unique truck ID")
+
+// maxIDAttempts bounds how many candidates CreateTruck tries before giving up
+const maxIDAttempts = 16
+
+// IDGenerator produces candidate truck IDs for callers that don't supply one.
+// Implementations must be safe for concurrent use.
+type IDGenerator interface {
+	NextID() (string, error)
+}
+
+// SequenceGenerator produces prefix + increasing sequence IDs such as truck-0001
+type SequenceGenerator struct {
+	prefix string
+	width  int
+	mu     sync.Mutex
+	next   uint64
+}
+
+// NewSequenceGenerator creates a generator whose sequence numbers are zero-padded to width digits
+func NewSequenceGenerator(prefix string, width int) *SequenceGenerator {
+	return &SequenceGenerator{prefix: prefix, width: width, next: 1}
+}
+
+// NextID returns the next ID in the sequence
+func (g *SequenceGenerator) NextID() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := fmt.Sprintf("%s%0*d", g.prefix, g.width, g.next)
+	g.next++
+	return id, nil
+}
+
+// ULIDGenerator produces lexicographically sortable ULIDs
+type ULIDGenerator struct{}
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NextID returns a new ULID built from the current time and 80 random bits
+func (ULIDGenerator) NextID() (string, error) {
+	var entropy [10]byte
+	if _, err := rand.Read(entropy[:]); err != nil {
+		return "", err
+	}
+	return encodeULID(uint64(time.Now().UnixMilli()), entropy), nil
+}
+
+// encodeULID encodes a 48-bit millisecond timestamp and 80 bits of entropy as 26 base32 characters
+func encodeULID(ms uint64, entropy [10]byte) string {
+	var out [26]byte
+	for i := 9; i >= 0; i-- {
+		out[i] = crockford[ms&31]
+		ms >>= 5
+	}
+
+	// Consume the entropy five bits at a time, most significant first
+	var acc uint64
+	bits := 0
+	pos := 10
+	for _, b := range entropy {
+		acc = acc<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[pos] = crockford[(acc>>bits)&31]
+			pos++
+		}
+	}
+	return string(out[:])
+}
+
+// TemplateGenerator expands a template for each ID. Supported placeholders are
+// {seq} (increasing counter), {seq:N} (counter padded to N digits),
+// {date} (UTC date as YYYYMMDD) and {rand} (8 random hex characters).
+type TemplateGenerator struct {
+	template string
+	mu       sync.Mutex
+	next     uint64
+}
+
+// NewTemplateGenerator validates the template and creates a generator for it
+func NewTemplateGenerator(template string) (*TemplateGenerator, error) {
+	g := &TemplateGenerator{template: template, next: 1}
+	if _, err := g.expand(0); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// NextID expands the template with the next sequence number
+func (g *TemplateGenerator) NextID() (string, error) {
+	g.mu.Lock()
+	seq := g.next
+	g.next++
+	g.mu.Unlock()
+
+	return g.expand(seq)
+}
+
+// expand substitutes every placeholder in the template
+func (g *TemplateGenerator) expand(seq uint64) (string, error) {
+	var b strings.Builder
+	rest := g.template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in ID template %q", g.template)
+		}
+		b.WriteString(rest[:open])
+		name := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		switch {
+		case name == "seq":
+			b.WriteString(strconv.FormatUint(seq, 10))
+		case strings.HasPrefix(name, "seq:"):
+			width, err := strconv.Atoi(name[len("seq:"):])
+			if err != nil || width <= 0 {
+				return "", fmt.Errorf("invalid width in placeholder {%s}", name)
+			}
+			fmt.Fprintf(&b, "%0*d", width, seq)
+		case name == "date":
+			b.WriteString(time.Now().UTC().Format("20060102"))
+		case name == "rand":
+			var r [4]byte
+			if _, err := rand.Read(r[:]); err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%x", r)
+		default:
+			return "", fmt.Errorf("unknown placeholder {%s} in ID template", name)
+		}
+	}
+	return b.String(), nil
+}
+
+// SetIDGenerator configures how CreateTruck generates IDs
+func (tm *truckManager) SetIDGenerator(g IDGenerator) {
+	tm.Lock()
+	defer tm.Unlock()
+	tm.idGen = g
+}
+
+// CreateTruck adds a truck with a generated ID and returns that ID. Generated
+// IDs that collide with existing trucks or trucks in the recycle bin are
+// skipped, so a removed truck can still be restored. Without a configured
+// generator, IDs of the form truck-1, truck-2, ... are used.
+func (tm *truckManager) CreateTruck(capacity int) (id string, err error) {
+	var after *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(context.Background(), "add", id, start, nil, after, err) }()
+
+	if capacity < 0 {
+		return "", ErrInvalidCargo
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if err := tm.checkLimits(1, capacity); err != nil {
+		return "", err
+	}
+	if tm.idGen == nil {
+		tm.idGen = NewSequenceGenerator("truck-", 0)
+	}
+
+	for attempt := 0; attempt < maxIDAttempts; attempt++ {
+		id, err := tm.idGen.NextID()
+		if err != nil {
+			return "", err
+		}
+		if id == "" {
+			return "", ErrEmptyID
+		}
+		if _, exist := tm.trucks[id]; exist {
+			continue
+		}
+		if _, removed := tm.recycled[id]; removed {
+			continue
+		}
+
+		if err := tm.addLocked(Truck{ID: id, Capacity: capacity}); err != nil {
+			return "", err
+		}
+		after = tm.snapshotLocked(id)
+		tm.auditLocked(context.Background(), "add", id, nil, after)
+		return id, nil
+	}
+	return "", ErrIDCollision
+}
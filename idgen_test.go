@@ -0,0 +1,104 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateTruckDefaultGenerator(t *testing.T) {
+	manager := NewFleetManager()
+
+	id, err := manager.CreateTruck(100)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != "truck-1" {
+		t.Errorf("Expected ID truck-1, got %s", id)
+	}
+
+	truck, err := manager.GetTruck(id)
+	if err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck with capacity 100, got %+v, %v", truck, err)
+	}
+}
+
+func TestCreateTruckSkipsCollisions(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("T-001", 1)
+	manager.AddTruck("T-002", 1)
+	manager.SetIDGenerator(NewSequenceGenerator("T-", 3))
+
+	id, err := manager.CreateTruck(5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != "T-003" {
+		t.Errorf("Expected ID T-003, got %s", id)
+	}
+}
+
+type constantGenerator string
+
+func (g constantGenerator) NextID() (string, error) { return string(g), nil }
+
+func TestCreateTruckSkipsRecycledIDs(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("truck-1", 1)
+	manager.RemoveTruck("truck-1")
+
+	id, err := manager.CreateTruck(5)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != "truck-2" {
+		t.Errorf("Expected ID truck-2, got %s", id)
+	}
+	if err := manager.RestoreTruck("truck-1"); err != nil {
+		t.Errorf("Expected truck-1 to stay restorable, got %v", err)
+	}
+}
+
+func TestCreateTruckCollisionLimit(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("fixed", 1)
+	manager.SetIDGenerator(constantGenerator("fixed"))
+
+	if _, err := manager.CreateTruck(1); err != ErrIDCollision {
+		t.Errorf("Expected ID collision error, got %v", err)
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	first, _ := ULIDGenerator{}.NextID()
+	time.Sleep(2 * time.Millisecond)
+	second, _ := ULIDGenerator{}.NextID()
+
+	if !regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`).MatchString(first) {
+		t.Errorf("Expected a 26 character ULID, got %s", first)
+	}
+	if first[:10] >= second[:10] {
+		t.Errorf("Expected ULIDs to sort by time, got %s then %s", first, second)
+	}
+}
+
+func TestTemplateGenerator(t *testing.T) {
+	g, err := NewTemplateGenerator("FL-{date}-{seq:4}")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	id, _ := g.NextID()
+	want := "FL-" + time.Now().UTC().Format("20060102") + "-0001"
+	if id != want {
+		t.Errorf("Expected %s, got %s", want, id)
+	}
+
+	if _, err := NewTemplateGenerator("FL-{unknown}"); err == nil {
+		t.Errorf("Expected error for unknown placeholder")
+	}
+	if _, err := NewTemplateGenerator("FL-{seq"); err == nil || !strings.Contains(err.Error(), "unterminated") {
+		t.Errorf("Expected unterminated placeholder error, got %v", err)
+	}
+}
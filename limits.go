@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFleetLimitReached is returned when a change would exceed the configured fleet limits
+var ErrFleetLimitReached = errors.New("fleet limit reached")
+
+// FleetLimits bounds the size of a fleet. Zero values mean unlimited.
+type FleetLimits struct {
+	MaxTrucks        int // maximum number of registered trucks
+	MaxTotalCapacity int // maximum sum of cargo capacity across all trucks
+}
+
+// SetLimits replaces the fleet limits. Lowering a limit below current usage
+// does not remove trucks; it only rejects further growth.
+func (tm *truckManager) SetLimits(limits FleetLimits) error {
+	if limits.MaxTrucks < 0 || limits.MaxTotalCapacity < 0 {
+		return fmt.Errorf("fleet limits cannot be negative")
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.limits = limits
+	return nil
+}
+
+// Limits returns the current fleet limits
+func (tm *truckManager) Limits() FleetLimits {
+	tm.RLock()
+	defer tm.RUnlock()
+	return tm.limits
+}
+
+// checkLimits reports whether adding trucks trucks and capacityDelta capacity
+// stays within the limits. Callers must hold the write lock.
+func (tm *truckManager) checkLimits(trucks, capacityDelta int) error {
+	if limit := tm.limits.MaxTrucks; limit > 0 && trucks > 0 && len(tm.trucks)+trucks > limit {
+		return fmt.Errorf("%w: fleet already has %d of %d trucks", ErrFleetLimitReached, len(tm.trucks), limit)
+	}
+	if limit := tm.limits.MaxTotalCapacity; limit > 0 && capacityDelta > 0 {
+		if total := int(tm.totalCapacity.Load()) + capacityDelta; total > limit {
+			return fmt.Errorf("%w: total capacity would be %d, limit is %d", ErrFleetLimitReached, total, limit)
+		}
+	}
+	return nil
+}
+
+// reserveCapacity adds delta to the fleet's total capacity unless that
+// would exceed the limit. Concurrent updates to trucks in different shards
+// may race for the last of the capacity, so the check and the addition are
+// one atomic step. Callers must hold the read or write lock.
+func (tm *truckManager) reserveCapacity(delta int) error {
+	limit := tm.limits.MaxTotalCapacity
+	for {
+		current := tm.totalCapacity.Load()
+		total := int(current) + delta
+		if limit > 0 && delta > 0 && total > limit {
+			return fmt.Errorf("%w: total capacity would be %d, limit is %d", ErrFleetLimitReached, total, limit)
+		}
+		if tm.totalCapacity.CompareAndSwap(current, int64(total)) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxTrucksLimit(t *testing.T) {
+	manager := NewFleetManager()
+	manager.SetLimits(FleetLimits{MaxTrucks: 2})
+
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+
+	if err := manager.AddTruck("3", 100); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error, got %v", err)
+	}
+	if _, err := manager.CreateTruck(100); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error from CreateTruck, got %v", err)
+	}
+
+	// Removing a truck frees a slot
+	manager.RemoveTruck("1")
+	if err := manager.AddTruck("3", 100); err != nil {
+		t.Errorf("Expected no error after removal, got %v", err)
+	}
+}
+
+func TestMaxTotalCapacityLimit(t *testing.T) {
+	manager := NewFleetManager()
+	manager.SetLimits(FleetLimits{MaxTotalCapacity: 1000})
+
+	manager.AddTruck("1", 600)
+	if err := manager.AddTruck("2", 500); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error, got %v", err)
+	}
+
+	manager.AddTruck("2", 400)
+	if err := manager.UpdateTruckCargo("1", 700); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error on update, got %v", err)
+	}
+
+	// Shrinking cargo is always allowed
+	if err := manager.UpdateTruckCargo("1", 100); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 100 {
+		t.Errorf("Expected truck capacity to be 100, got %d", truck.Capacity)
+	}
+}
+
+func TestSetLimitsRuntime(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+
+	if err := manager.SetLimits(FleetLimits{MaxTrucks: 1}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(manager.trucks) != 2 {
+		t.Errorf("Expected lowering the limit to keep existing trucks, got %d", len(manager.trucks))
+	}
+	if err := manager.AddTruck("3", 100); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error, got %v", err)
+	}
+
+	manager.SetLimits(FleetLimits{})
+	if err := manager.AddTruck("3", 100); err != nil {
+		t.Errorf("Expected no error with limits cleared, got %v", err)
+	}
+
+	if err := manager.SetLimits(FleetLimits{MaxTrucks: -1}); err == nil {
+		t.Errorf("Expected error for negative limit")
+	}
+}
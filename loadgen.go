@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operation kinds driven by the load generator
+const (
+	OpAdd    = "add"
+	OpGet    = "get"
+	OpUpdate = "update"
+	OpRemove = "remove"
+	OpLoad   = "load"
+	OpUnload = "unload"
+)
+
+// loadOps lists the operation kinds in a fixed order so runs are reproducible
+var loadOps = []string{OpAdd, OpGet, OpUpdate, OpRemove, OpLoad, OpUnload}
+
+// LoadConfig describes the operation mix and pacing of a load run
+type LoadConfig struct {
+	Duration time.Duration  // how long to generate load
+	Rate     int            // target operations per second across all workers, 0 for unthrottled
+	Workers  int            // number of concurrent callers
+	Trucks   int            // size of the truck ID space operations are drawn from
+	Mix      map[string]int // relative weight of each operation kind
+	Seed     int64          // seed for the per-worker random sources
+}
+
+// OpStats holds the outcome of one operation kind during a load run
+type OpStats struct {
+	Count  int
+	Errors int
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+// LoadReport summarizes a load run
+type LoadReport struct {
+	Elapsed    time.Duration
+	Total      int
+	Throughput float64 // operations per second
+	Ops        map[string]OpStats
+}
+
+// DefaultLoadConfig returns a read-heavy mix suitable for a quick capacity check
+func DefaultLoadConfig() LoadConfig {
+	return LoadConfig{
+		Duration: 5 * time.Second,
+		Workers:  8,
+		Trucks:   1000,
+		Mix:      map[string]int{OpAdd: 1, OpGet: 8, OpUpdate: 1, OpRemove: 1, OpLoad: 2, OpUnload: 2},
+		Seed:     1,
+	}
+}
+
+// RunLoad drives the configured operation mix against the manager and
+// reports throughput and latency percentiles per operation kind
+func RunLoad(manager FleetManager, cfg LoadConfig) (LoadReport, error) {
+	if cfg.Workers <= 0 || cfg.Trucks <= 0 || cfg.Duration <= 0 {
+		return LoadReport{}, fmt.Errorf("load config needs positive duration, workers and trucks")
+	}
+	// Past one operation per nanosecond the ticker interval rounds to zero
+	if cfg.Rate > int(time.Second) {
+		return LoadReport{}, fmt.Errorf("load rate %d exceeds %d operations per second", cfg.Rate, int(time.Second))
+	}
+	totalWeight := 0
+	for _, op := range loadOps {
+		totalWeight += cfg.Mix[op]
+	}
+	if totalWeight <= 0 {
+		return LoadReport{}, fmt.Errorf("load mix has no operations")
+	}
+
+	// A shared token channel paces all workers when a rate is set
+	var tokens <-chan time.Time
+	if cfg.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+		defer ticker.Stop()
+		tokens = ticker.C
+	}
+
+	type sample struct {
+		op      string
+		latency time.Duration
+		err     bool
+	}
+	results := make([][]sample, cfg.Workers)
+	deadline := time.Now().Add(cfg.Duration)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(cfg.Seed + int64(w)))
+			for time.Now().Before(deadline) {
+				if tokens != nil {
+					<-tokens
+				}
+				op := pickOp(rng, cfg.Mix, totalWeight)
+				id := "truck-" + strconv.Itoa(rng.Intn(cfg.Trucks))
+				capacity := rng.Intn(10000)
+				amount := 1 + rng.Intn(1000)
+
+				begin := time.Now()
+				var err error
+				switch op {
+				case OpAdd:
+					err = manager.AddTruck(id, capacity)
+				case OpGet:
+					_, err = manager.GetTruck(id)
+				case OpUpdate:
+					err = manager.UpdateTruckCargo(id, capacity)
+				case OpRemove:
+					err = manager.RemoveTruck(id)
+				case OpLoad:
+					err = manager.LoadCargo(id, amount)
+				case OpUnload:
+					err = manager.UnloadCargo(id, amount)
+				}
+				results[w] = append(results[w], sample{op: op, latency: time.Since(begin), err: err != nil})
+			}
+		}(w)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// Merge the per-worker samples into per-operation latency lists
+	latencies := make(map[string][]time.Duration)
+	report := LoadReport{Elapsed: elapsed, Ops: make(map[string]OpStats)}
+	for _, samples := range results {
+		for _, s := range samples {
+			stats := report.Ops[s.op]
+			stats.Count++
+			if s.err {
+				stats.Errors++
+			}
+			report.Ops[s.op] = stats
+			latencies[s.op] = append(latencies[s.op], s.latency)
+			report.Total++
+		}
+	}
+	for op, l := range latencies {
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+		stats := report.Ops[op]
+		stats.P50 = percentile(l, 50)
+		stats.P90 = percentile(l, 90)
+		stats.P99 = percentile(l, 99)
+		stats.Max = l[len(l)-1]
+		report.Ops[op] = stats
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Total) / elapsed.Seconds()
+	}
+	return report, nil
+}
+
+// pickOp chooses an operation kind according to the mix weights
+func pickOp(rng *rand.Rand, mix map[string]int, totalWeight int) string {
+	n := rng.Intn(totalWeight)
+	for _, op := range loadOps {
+		if n < mix[op] {
+			return op
+		}
+		n -= mix[op]
+	}
+	return loadOps[len(loadOps)-1]
+}
+
+// percentile returns the p-th percentile of sorted latencies using the nearest-rank method
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// parseMix parses a mix such as "add=1,get=8,update=2,remove=1,load=2,unload=2"
+func parseMix(s string) (map[string]int, error) {
+	mix := make(map[string]int)
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q", part)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %q", name)
+		}
+		switch name {
+		case OpAdd, OpGet, OpUpdate, OpRemove, OpLoad, OpUnload:
+			mix[name] = weight
+		default:
+			return nil, fmt.Errorf("unknown operation %q", name)
+		}
+	}
+	return mix, nil
+}
+
+// runLoadCommand runs a load test against an in-process manager and prints the report
+func runLoadCommand(args []string) error {
+	cfg := DefaultLoadConfig()
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	fs.DurationVar(&cfg.Duration, "duration", cfg.Duration, "how long to generate load")
+	fs.IntVar(&cfg.Rate, "rate", cfg.Rate, "target operations per second (0 for unthrottled)")
+	fs.IntVar(&cfg.Workers, "workers", cfg.Workers, "number of concurrent workers")
+	fs.IntVar(&cfg.Trucks, "trucks", cfg.Trucks, "size of the truck ID space")
+	fs.Int64Var(&cfg.Seed, "seed", cfg.Seed, "random seed")
+	mix := fs.String("mix", "add=1,get=8,update=1,remove=1,load=2,unload=2", "relative operation weights")
+	record := fs.String("record", "", "record mutating calls to this file for later replay")
+	scenario := fs.String("scenario", "", "seed the manager from this scenario file before the run")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var err error
+	if cfg.Mix, err = parseMix(*mix); err != nil {
+		return err
+	}
+
+	manager := NewFleetManager()
+	if *scenario != "" {
+		s, err := LoadScenarioFile(*scenario)
+		if err != nil {
+			return err
+		}
+		if _, err := SeedFleet(manager, s); err != nil {
+			return err
+		}
+	}
+
+	var target FleetManager = manager
+	var recorder *Recorder
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		recorder = NewRecorder(target, bufio.NewWriter(f))
+		target = recorder
+	}
+
+	report, err := RunLoad(target, cfg)
+	if err != nil {
+		return err
+	}
+	if recorder != nil {
+		if err := recorder.Flush(); err != nil {
+			return fmt.Errorf("writing recording: %w", err)
+		}
+	}
+
+	fmt.Printf("%d operations in %v (%.0f ops/s)\n", report.Total, report.Elapsed.Round(time.Millisecond), report.Throughput)
+	fmt.Printf("%-8s %10s %8s %10s %10s %10s %10s\n", "op", "count", "errors", "p50", "p90", "p99", "max")
+	for _, op := range loadOps {
+		stats, ok := report.Ops[op]
+		if !ok {
+			continue
+		}
+		fmt.Printf("%-8s %10d %8d %10v %10v %10v %10v\n", op, stats.Count, stats.Errors, stats.P50, stats.P90, stats.P99, stats.Max)
+	}
+	return nil
+}
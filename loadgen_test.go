@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunLoad(t *testing.T) {
+	manager := NewFleetManager()
+	cfg := DefaultLoadConfig()
+	cfg.Duration = 50 * time.Millisecond
+	cfg.Workers = 4
+	cfg.Trucks = 50
+
+	report, err := RunLoad(manager, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Total == 0 {
+		t.Errorf("Expected some operations, got 0")
+	}
+
+	sum := 0
+	for _, stats := range report.Ops {
+		sum += stats.Count
+		if stats.P50 > stats.P99 || stats.P99 > stats.Max {
+			t.Errorf("Expected ordered percentiles, got p50=%v p99=%v max=%v", stats.P50, stats.P99, stats.Max)
+		}
+	}
+	if sum != report.Total {
+		t.Errorf("Expected per-op counts to sum to %d, got %d", report.Total, sum)
+	}
+}
+
+func TestRunLoadRateLimit(t *testing.T) {
+	manager := NewFleetManager()
+	cfg := DefaultLoadConfig()
+	cfg.Duration = 100 * time.Millisecond
+	cfg.Rate = 100
+
+	report, err := RunLoad(manager, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Total > 20 {
+		t.Errorf("Expected at most 20 operations at 100 ops/s, got %d", report.Total)
+	}
+}
+
+func TestRunLoadRateTooHigh(t *testing.T) {
+	cfg := DefaultLoadConfig()
+	cfg.Duration = 10 * time.Millisecond
+	cfg.Rate = int(time.Second) + 1
+
+	if _, err := RunLoad(NewFleetManager(), cfg); err == nil {
+		t.Errorf("Expected an error for a rate above one operation per nanosecond")
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := parseMix("add=1, get=3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mix[OpAdd] != 1 || mix[OpGet] != 3 {
+		t.Errorf("Expected add=1 get=3, got %v", mix)
+	}
+
+	if _, err := parseMix("fly=1"); err == nil {
+		t.Errorf("Expected error for unknown operation")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if p := percentile(latencies, 50); p != 5 {
+		t.Errorf("Expected p50 to be 5, got %v", p)
+	}
+	if p := percentile(latencies, 99); p != 10 {
+		t.Errorf("Expected p99 to be 10, got %v", p)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrInvalidLocation is returned for coordinates outside the valid range
+var ErrInvalidLocation = errors.New("invalid location")
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// geoCellDegrees is the size of one cell of the location index, about 11 km
+// of latitude
+const geoCellDegrees = 0.1
+
+// Location is a truck's last reported GPS position
+type Location struct {
+	Lat  float64   `json:"lat"`
+	Lon  float64   `json:"lon"`
+	Time time.Time `json:"time"`
+}
+
+// validate checks the coordinates are on the globe
+func (l Location) validate() error {
+	if math.IsNaN(l.Lat) || math.IsNaN(l.Lon) || l.Lat < -90 || l.Lat > 90 || l.Lon < -180 || l.Lon > 180 {
+		return ErrInvalidLocation
+	}
+	return nil
+}
+
+// NearbyTruck is a truck found by GetTrucksNear with its distance from the search point
+type NearbyTruck struct {
+	Truck      Truck
+	DistanceKm float64
+}
+
+// geoCell identifies one cell of the location index
+type geoCell struct {
+	lat, lon int
+}
+
+// geoLonCells is the number of cells around a line of latitude
+var geoLonCells = int(math.Round(360 / geoCellDegrees))
+
+// cellOf returns the cell holding a point
+func cellOf(lat, lon float64) geoCell {
+	c := geoCell{lat: int(math.Floor(lat / geoCellDegrees)), lon: int(math.Floor((lon + 180) / geoCellDegrees))}
+	// 180° east is the same meridian as 180° west
+	c.lon %= geoLonCells
+	return c
+}
+
+// geoIndex buckets located trucks by grid cell, so a radius search only
+// looks at the cells the circle overlaps. It has its own lock because
+// location updates run under the manager's read lock.
+type geoIndex struct {
+	mu    sync.Mutex
+	cells map[geoCell]map[string]struct{}
+	at    map[string]geoCell // the cell each located truck is in
+}
+
+// move records that truck id is now at loc, or forgets it if loc is nil
+func (g *geoIndex) move(id string, loc *Location) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.cells == nil {
+		g.cells = make(map[geoCell]map[string]struct{})
+		g.at = make(map[string]geoCell)
+	}
+	if old, exist := g.at[id]; exist {
+		delete(g.cells[old], id)
+		if len(g.cells[old]) == 0 {
+			delete(g.cells, old)
+		}
+		delete(g.at, id)
+	}
+	if loc == nil {
+		return
+	}
+	cell := cellOf(loc.Lat, loc.Lon)
+	if g.cells[cell] == nil {
+		g.cells[cell] = make(map[string]struct{})
+	}
+	g.cells[cell][id] = struct{}{}
+	g.at[id] = cell
+}
+
+// reset forgets every truck
+func (g *geoIndex) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cells = nil
+	g.at = nil
+}
+
+// candidates returns the IDs of trucks in cells overlapping the circle.
+// When the circle spans more cells than are occupied, as near the poles or
+// for very large radii, it returns every located truck instead.
+func (g *geoIndex) candidates(lat, lon, radiusKm float64) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	latSpan := radiusKm / (earthRadiusKm * math.Pi / 180)
+	minLat := int(math.Floor(math.Max(lat-latSpan, -90) / geoCellDegrees))
+	maxLat := int(math.Floor(math.Min(lat+latSpan, 90) / geoCellDegrees))
+
+	// The widest point of the circle is at the latitude closest to a pole
+	widest := math.Min(math.Abs(lat)+latSpan, 90)
+	lonCells := geoLonCells
+	if cos := math.Cos(widest * math.Pi / 180); cos > 1e-9 {
+		lonSpan := latSpan / cos
+		lonCells = 2*int(math.Ceil(lonSpan/geoCellDegrees)) + 1
+	}
+
+	var ids []string
+	if lonCells >= geoLonCells || (maxLat-minLat+1)*lonCells > len(g.cells) {
+		for id := range g.at {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	center := cellOf(lat, lon)
+	for la := minLat; la <= maxLat; la++ {
+		for i := -lonCells / 2; i <= lonCells/2; i++ {
+			lo := ((center.lon+i)%geoLonCells + geoLonCells) % geoLonCells
+			for id := range g.cells[geoCell{lat: la, lon: lo}] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// UpdateLocation records a truck's GPS position at ts. A report older than
+// the position already held is ignored, so telemetry may arrive out of
+// order. Location updates take only the truck's shard lock and don't change
+// its Version, so a steady feed doesn't hold up other work or break
+// compare-and-swap updates.
+func (tm *truckManager) UpdateLocation(id string, lat, lon float64, ts time.Time) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+	loc := Location{Lat: lat, Lon: lon, Time: ts}
+	if err := loc.validate(); err != nil {
+		return err
+	}
+
+	unlock, err := tm.lockTruckContext(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	truck, exist := tm.truck(id)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if truck.Location != nil && ts.Before(truck.Location.Time) {
+		return nil
+	}
+	updated := truck.clone()
+	updated.Location = &loc
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+	old := *truck
+	tm.trucks[id].store(&updated)
+	tm.geo.move(id, updated.Location)
+	tm.emit(LocationChanged, id, &old, &updated)
+	tm.auditChangeLocked("location", id, &old, &updated)
+	return nil
+}
+
+// GetTrucksNear returns the trucks last seen within radiusKm of a point,
+// nearest first. Trucks that never reported a location are left out.
+func (tm *truckManager) GetTrucksNear(lat, lon, radiusKm float64) ([]NearbyTruck, error) {
+	if err := (Location{Lat: lat, Lon: lon}).validate(); err != nil {
+		return nil, err
+	}
+	if radiusKm < 0 || math.IsNaN(radiusKm) {
+		return nil, ErrInvalidLocation
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	var nearby []NearbyTruck
+	for _, id := range tm.geo.candidates(lat, lon, radiusKm) {
+		truck, exist := tm.truck(id)
+		if !exist || truck.Location == nil {
+			continue
+		}
+		if d := distanceKm(lat, lon, truck.Location.Lat, truck.Location.Lon); d <= radiusKm {
+			nearby = append(nearby, NearbyTruck{Truck: truck.clone(), DistanceKm: d})
+		}
+	}
+	sort.Slice(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKm != nearby[j].DistanceKm {
+			return nearby[i].DistanceKm < nearby[j].DistanceKm
+		}
+		return nearby[i].Truck.ID < nearby[j].Truck.ID
+	})
+	return nearby, nil
+}
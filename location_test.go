@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUpdateLocation(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	ts := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err := manager.UpdateLocation("1", -1.2921, 36.8219, ts); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.Location == nil || truck.Location.Lat != -1.2921 || truck.Location.Lon != 36.8219 || !truck.Location.Time.Equal(ts) {
+		t.Fatalf("Expected the reported location, got %+v", truck.Location)
+	}
+	if truck.Version != 1 {
+		t.Errorf("Expected location updates to leave the version at 1, got %d", truck.Version)
+	}
+
+	// An older report is ignored
+	manager.UpdateLocation("1", 0, 0, ts.Add(-time.Minute))
+	truck, _ = manager.GetTruck("1")
+	if truck.Location.Lat != -1.2921 {
+		t.Errorf("Expected the older report to be ignored, got %+v", truck.Location)
+	}
+
+	// Copies handed out don't share the location
+	truck.Location.Lat = 10
+	truck, _ = manager.GetTruck("1")
+	if truck.Location.Lat != -1.2921 {
+		t.Errorf("Expected the stored location to be unchanged, got %+v", truck.Location)
+	}
+}
+
+func TestUpdateLocationErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	for _, c := range [][2]float64{{91, 0}, {-91, 0}, {0, 181}, {0, -181}, {math.NaN(), 0}} {
+		if err := manager.UpdateLocation("1", c[0], c[1], time.Now()); err != ErrInvalidLocation {
+			t.Errorf("Expected ErrInvalidLocation for %v, got %v", c, err)
+		}
+	}
+	if err := manager.UpdateLocation("x", 0, 0, time.Now()); err != ErrTruckNotFound {
+		t.Errorf("Expected ErrTruckNotFound, got %v", err)
+	}
+	if err := manager.UpdateLocation("", 0, 0, time.Now()); err != ErrEmptyID {
+		t.Errorf("Expected ErrEmptyID, got %v", err)
+	}
+	if _, err := manager.GetTrucksNear(0, 0, -1); err != ErrInvalidLocation {
+		t.Errorf("Expected ErrInvalidLocation for a negative radius, got %v", err)
+	}
+}
+
+func TestGetTrucksNear(t *testing.T) {
+	manager := NewFleetManager()
+	now := time.Now()
+	// Nairobi, Thika (about 40 km away), Mombasa (about 440 km away) and no location
+	for id, pos := range map[string][2]float64{"nairobi": {-1.2921, 36.8219}, "thika": {-1.0333, 37.0693}, "mombasa": {-4.0435, 39.6682}} {
+		manager.AddTruck(id, 100)
+		manager.UpdateLocation(id, pos[0], pos[1], now)
+	}
+	manager.AddTruck("unknown", 100)
+
+	near, err := manager.GetTrucksNear(-1.2921, 36.8219, 50)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(near) != 2 || near[0].Truck.ID != "nairobi" || near[1].Truck.ID != "thika" {
+		t.Fatalf("Expected nairobi then thika, got %+v", near)
+	}
+	if near[0].DistanceKm != 0 || near[1].DistanceKm < 35 || near[1].DistanceKm > 45 {
+		t.Errorf("Expected distances of 0 and about 40 km, got %g and %g", near[0].DistanceKm, near[1].DistanceKm)
+	}
+
+	all, _ := manager.GetTrucksNear(-1.2921, 36.8219, 1000)
+	if len(all) != 3 || all[2].Truck.ID != "mombasa" {
+		t.Errorf("Expected all three located trucks, got %+v", all)
+	}
+
+	// Removed trucks leave the index
+	manager.RemoveTruck("thika")
+	near, _ = manager.GetTrucksNear(-1.2921, 36.8219, 50)
+	if len(near) != 1 {
+		t.Errorf("Expected only nairobi, got %+v", near)
+	}
+}
+
+func TestGetTrucksNearAntimeridian(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("east", 100)
+	manager.AddTruck("west", 100)
+	for i := 0; i < 50; i++ {
+		id := fmt.Sprintf("far-%d", i)
+		manager.AddTruck(id, 100)
+		manager.UpdateLocation(id, float64(i), 0, time.Now())
+	}
+	manager.UpdateLocation("east", 0, 179.95, time.Now())
+	manager.UpdateLocation("west", 0, -179.95, time.Now())
+
+	near, _ := manager.GetTrucksNear(0, 179.99, 20)
+	if len(near) != 2 {
+		t.Errorf("Expected both trucks across the antimeridian, got %+v", near)
+	}
+}
+
+func TestGetTrucksNearMatchesScan(t *testing.T) {
+	manager := NewFleetManager()
+	for i := 0; i < 200; i++ {
+		id := fmt.Sprintf("truck-%d", i)
+		manager.AddTruck(id, 100)
+		manager.UpdateLocation(id, -2+float64(i%20)*0.2, 36+float64(i/20)*0.2, time.Now())
+	}
+
+	for _, radius := range []float64{0, 5, 25, 60, 150} {
+		near, _ := manager.GetTrucksNear(-1, 37, radius)
+		expected := 0
+		trucks, _ := manager.ListTrucks(0, 1000)
+		for _, truck := range trucks {
+			if distanceKm(-1, 37, truck.Location.Lat, truck.Location.Lon) <= radius {
+				expected++
+			}
+		}
+		if len(near) != expected {
+			t.Errorf("Expected %d trucks within %g km, got %d", expected, radius, len(near))
+		}
+	}
+}
+
+func TestLocationSurvivesStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	manager, _ := OpenTruckManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+	manager.UpdateLocation("1", 10, 20, time.Now())
+
+	reopened, _ := OpenTruckManager(WithStorage(storage))
+	near, _ := reopened.GetTrucksNear(10, 20, 1)
+	if len(near) != 1 || near[0].Truck.ID != "1" {
+		t.Errorf("Expected truck 1 after reload, got %+v", near)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+)
+
+// requestIDKey is the context key for the caller's request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a request ID. Mutations made
+// with the returned context include it in their log records.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// snapshotLocked copies the truck for a log record or audit entry, or
+// returns nil if it doesn't exist or neither is configured. Callers must
+// hold the lock.
+func (tm *truckManager) snapshotLocked(id string) *Truck {
+	if tm.logger == nil && tm.audit == nil {
+		return nil
+	}
+	truck, exist := tm.truck(id)
+	if !exist {
+		return nil
+	}
+	c := truck.clone()
+	return &c
+}
+
+// logMutation records the outcome of one mutation. before and after are the
+// truck's state either side of it and are nil when there is none.
+func (tm *truckManager) logMutation(ctx context.Context, op, id string, before, after *Truck, err error) {
+	if tm.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("op", op), slog.String("truck_id", id)}
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+	if before != nil {
+		attrs = append(attrs, slog.Int("old_capacity", before.Capacity), slog.Int("old_load", before.CurrentLoad))
+	}
+	if after != nil {
+		attrs = append(attrs, slog.Int("new_capacity", after.Capacity), slog.Int("new_load", after.CurrentLoad))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		tm.logger.LogAttrs(ctx, slog.LevelWarn, "truck mutation failed", attrs...)
+		return
+	}
+	tm.logger.LogAttrs(ctx, slog.LevelInfo, "truck mutation", attrs...)
+}
+
+// logBatch records the outcome of every item in a finished batch
+func (tm *truckManager) logBatch(op string, result BatchResult) {
+	if tm.logger == nil {
+		return
+	}
+	ctx := context.Background()
+	for _, id := range result.Succeeded {
+		tm.logMutation(ctx, op, id, nil, nil, nil)
+	}
+	for _, failed := range result.Failed {
+		tm.logMutation(ctx, op, failed.ID, nil, nil, failed.Err)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the JSON log lines written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected JSON log line, got %q", line)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLoggerRecordsMutations(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewFleetManager(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	ctx := WithRequestID(context.Background(), "req-42")
+
+	manager.AddTruckContext(ctx, "1", 100)
+	manager.LoadCargoContext(ctx, "1", 30)
+	manager.UpdateTruckCargoContext(ctx, "1", 200)
+	manager.RemoveTruckContext(ctx, "1")
+
+	records := logRecords(t, &buf)
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 log records, got %d", len(records))
+	}
+	for i, op := range []string{"add", "load", "update", "remove"} {
+		if records[i]["op"] != op || records[i]["truck_id"] != "1" || records[i]["request_id"] != "req-42" {
+			t.Errorf("Expected %s of truck 1 for req-42, got %v", op, records[i])
+		}
+	}
+
+	load := records[1]
+	if load["old_load"] != 0.0 || load["new_load"] != 30.0 {
+		t.Errorf("Expected load to go from 0 to 30, got %v", load)
+	}
+	update := records[2]
+	if update["old_capacity"] != 100.0 || update["new_capacity"] != 200.0 {
+		t.Errorf("Expected capacity to go from 100 to 200, got %v", update)
+	}
+	if _, ok := records[3]["new_capacity"]; ok {
+		t.Errorf("Expected no new state for a removal, got %v", records[3])
+	}
+}
+
+func TestLoggerRecordsErrors(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewFleetManager(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	manager.AddTruck("1", 100)
+	buf.Reset()
+
+	if err := manager.LoadCargo("1", 500); !errors.Is(err, ErrCapacityExceeded) {
+		t.Fatalf("Expected capacity exceeded error, got %v", err)
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["level"] != "WARN" || records[0]["error"] != ErrCapacityExceeded.Error() {
+		t.Errorf("Expected one warning carrying the error, got %v", records)
+	}
+	if _, ok := records[0]["request_id"]; ok {
+		t.Errorf("Expected no request ID without one in the context, got %v", records[0])
+	}
+}
+
+func TestLoggerRecordsBatchItems(t *testing.T) {
+	var buf bytes.Buffer
+	manager := NewFleetManager(WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	manager.AddTrucks([]Truck{{ID: "1", Capacity: 100}, {ID: "", Capacity: 100}})
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 || records[0]["op"] != "add" || records[1]["level"] != "WARN" {
+		t.Errorf("Expected one success and one failure, got %v", records)
+	}
+}
@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Error definitions for truck management operations
@@ -12,126 +18,549 @@ var (
 	ErrTruckExist    = errors.New("truck already exists")
 	ErrInvalidCargo  = errors.New("invalid cargo value")
 	ErrEmptyID       = errors.New("truck ID cannot be empty")
+	ErrInvalidPage   = errors.New("invalid pagination parameters")
+
+	ErrCapacityExceeded  = errors.New("cargo exceeds truck capacity")
+	ErrInsufficientCargo = errors.New("not enough cargo on truck")
+	ErrVersionConflict   = errors.New("truck was modified since it was read")
 )
 
 // FleetManager defines the interface for managing a fleet of trucks
 type FleetManager interface {
-	AddTruck(id string, cargo int) error
+	AddTruck(id string, capacity int) error
 	GetTruck(id string) (Truck, error)
 	RemoveTruck(id string) error
-	UpdateTruckCargo(id string, cargo int) error
+	UpdateTruckCargo(id string, capacity int) error
+	LoadCargo(id string, amount int) error
+	UnloadCargo(id string, amount int) error
+	ListTrucks(offset, limit int) ([]Truck, error)
+}
+
+// ContextFleetManager is FleetManager with cancellation and deadlines. Each
+// method returns the context's error if it is done before the operation is
+// applied.
+type ContextFleetManager interface {
+	AddTruckContext(ctx context.Context, id string, capacity int) error
+	GetTruckContext(ctx context.Context, id string) (Truck, error)
+	RemoveTruckContext(ctx context.Context, id string) error
+	UpdateTruckCargoContext(ctx context.Context, id string, capacity int) error
+	LoadCargoContext(ctx context.Context, id string, amount int) error
+	UnloadCargoContext(ctx context.Context, id string, amount int) error
+	ListTrucksContext(ctx context.Context, offset, limit int) ([]Truck, error)
 }
 
-// Truck represents a truck with an ID and cargo capacity
+// Truck represents a truck with an ID, the most cargo it can carry and the
+// cargo currently on board. Trucks returned by the manager are copies;
+// modifying them does not change the fleet.
 type Truck struct {
-	ID    string
-	Cargo int
+	ID          string `json:"id"`
+	Capacity    int    `json:"capacity"`
+	CurrentLoad int    `json:"current_load"`
+	DriverID    string `json:"driver_id,omitempty"`
+	RouteID     string `json:"route_id,omitempty"`
+	YardID      string `json:"yard_id,omitempty"`
+	// Version starts at 1 and increases with every change to the truck
+	Version uint64 `json:"version"`
+	// Custom holds values for the custom fields defined with DefineField
+	Custom map[string]string `json:"custom,omitempty"`
+	// FuelCapacity is the size of the fuel tank and FuelLevel the fuel in it, in liters
+	FuelCapacity float64 `json:"fuel_capacity,omitempty"`
+	FuelLevel    float64 `json:"fuel_level,omitempty"`
+	// Location is the last reported GPS position; nil until one is reported
+	Location *Location `json:"location,omitempty"`
+	// Tags holds free-form key/value labels such as region=west; see SetTag
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// validate checks a truck's fields, including that it isn't overloaded
+func (t Truck) validate() error {
+	if t.ID == "" {
+		return ErrEmptyID
+	}
+	if t.Capacity < 0 || t.CurrentLoad < 0 {
+		return ErrInvalidCargo
+	}
+	if !finite(t.FuelCapacity, t.FuelLevel) || t.FuelCapacity < 0 || t.FuelLevel < 0 || t.FuelLevel > t.FuelCapacity {
+		return ErrInvalidFuel
+	}
+	if t.Location != nil {
+		if err := t.Location.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateTags(t.Tags); err != nil {
+		return err
+	}
+	if t.CurrentLoad > t.Capacity {
+		return ErrCapacityExceeded
+	}
+	return nil
 }
 
 // truckManager implements the FleetManager interface
 type truckManager struct {
-	trucks map[string]*Truck
+	trucks map[string]*truckSlot
+	ids    []string // sorted truck IDs, kept in step with trucks for ordered listing
+	idGen  IDGenerator
+	limits FleetLimits
+	// storage persists every mutation when configured; nil keeps the fleet in memory only
+	storage Storage
+	// totalCapacity is the sum of capacity across all trucks, maintained for limit checks
+	totalCapacity atomic.Int64
+	events        eventBus
+	// heldEvents collects events during an atomic batch; nil means publish immediately
+	heldEvents *[]FleetEvent
+	// auditHeld stops the set*Locked helpers auditing while an import audits whole rows
+	auditHeld bool
+	// rrLast is the last truck handed out by round-robin selection
+	rrLast string
+	rrMu   sync.Mutex
+	// maintenance holds each truck's planned downtime, ordered by start
+	maintenance  map[string][]MaintenanceWindow
+	nextWindowID int
+	// drivers holds every registered driver by ID
+	drivers map[string]*Driver
+	// routes holds every registered route by ID
+	routes map[string]*Route
+	// yards holds every registered yard by ID
+	yards map[string]*yard
+	// docks and appointments track dock bookings; appointments are indexed by ID
+	docks             map[string]*dock
+	appointments      map[int]*Appointment
+	nextAppointmentID int
+	// inboxes holds each driver's messages, oldest first; messages indexes them by ID
+	inboxes       map[string][]*Message
+	messages      map[int]*Message
+	nextMessageID int
+	// geo indexes trucks by last known location for GetTrucksNear
+	geo geoIndex
+	// fuel holds each truck's refuels and trips, oldest first
+	fuel map[string][]FuelRecord
+	// recycled holds removed trucks until retention passes; see RestoreTruck
+	recycled  map[string]*recycledTruck
+	retention time.Duration
+	// reservations holds the latest reservation of each truck; see ReserveTruck
+	reservations map[string]Reservation
+	// exportPolicies holds the export policy of each role; see ExportFleetAs
+	exportPolicies map[string]ExportPolicy
+	// tags indexes trucks by tag for GetTrucksByTag
+	tags tagIndex
+	// fieldDefs holds the custom fields defined on each entity by name
+	fieldDefs map[FieldEntity]map[string]FieldDef
+	// logger receives a record for each mutation; nil disables logging
+	logger *slog.Logger
+	// metrics counts mutations when configured
+	metrics *Metrics
+	// audit records every successful change when configured
+	audit *AuditLog
+	// shards serialize updates to individual trucks; see lockTruckContext
+	shards []sync.Mutex
 	sync.RWMutex
 }
 
-// NewTruckManager creates a new instance of FleetManager
-func NewTruckManager() truckManager {
+// NewFleetManager creates an empty fleet configured by opts. Without
+// WithStorage the fleet lives in memory only; use OpenTruckManager to load
+// a fleet already held in storage.
+func NewFleetManager(opts ...Option) *truckManager {
+	o := applyOptions(opts)
+	tm := newTruckManager(o)
+	tm.startPurge(o)
+	return &tm
+}
+
+// NewTruckManager creates an empty fleet configured by opts.
+//
+// Deprecated: NewTruckManager returns the manager by value, so copying the
+// result copies its locks, and the value itself doesn't satisfy
+// FleetManager. Use NewFleetManager, which returns a pointer. WithPurgeEvery
+// is ignored here, since the purge loop would run on a manager the caller
+// only ever holds a copy of.
+func NewTruckManager(opts ...Option) truckManager {
+	return newTruckManager(applyOptions(opts))
+}
+
+// newTruckManager builds a manager from collected options
+func newTruckManager(o managerOptions) truckManager {
 	return truckManager{
-		trucks: make(map[string]*Truck),
+		trucks:         make(map[string]*truckSlot, o.capacity),
+		ids:            make([]string, 0, o.capacity),
+		maintenance:    make(map[string][]MaintenanceWindow),
+		drivers:        make(map[string]*Driver),
+		routes:         make(map[string]*Route),
+		yards:          make(map[string]*yard),
+		docks:          make(map[string]*dock),
+		appointments:   make(map[int]*Appointment),
+		inboxes:        make(map[string][]*Message),
+		messages:       make(map[int]*Message),
+		fieldDefs:      make(map[FieldEntity]map[string]FieldDef),
+		tags:           make(tagIndex),
+		fuel:           make(map[string][]FuelRecord),
+		recycled:       make(map[string]*recycledTruck),
+		retention:      o.retention,
+		reservations:   make(map[string]Reservation),
+		exportPolicies: make(map[string]ExportPolicy),
+		storage:        o.storage,
+		logger:         o.logger,
+		metrics:        o.metrics,
+		audit:          o.audit,
+		shards:         newShards(o.shards),
 	}
 }
 
-// AddTruck adds a new truck to the fleet with the specified ID and cargo capacity
-func (tm *truckManager) AddTruck(id string, cargo int) error {
-	tm.Lock()
-	defer tm.Unlock()
+// AddTruck adds a new, empty truck to the fleet with the specified ID and cargo capacity
+func (tm *truckManager) AddTruck(id string, capacity int) error {
+	return tm.AddTruckContext(context.Background(), id, capacity)
+}
+
+// AddTruckContext is AddTruck with cancellation
+func (tm *truckManager) AddTruckContext(ctx context.Context, id string, capacity int) (err error) {
+	var after *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(ctx, "add", id, start, nil, after, err) }()
 
 	// Validate input parameters
 	if id == "" {
 		return ErrEmptyID
 	}
-	if cargo < 0 {
+	if capacity < 0 {
 		return ErrInvalidCargo
 	}
 
-	// Check if truck already exists
-	if _, exist := tm.trucks[id]; exist {
-		return ErrTruckExist
+	if err := tm.lockContext(ctx); err != nil {
+		return err
+	}
+	defer tm.Unlock()
+
+	if err := tm.addLocked(Truck{ID: id, Capacity: capacity}); err != nil {
+		return err
 	}
+	after = tm.snapshotLocked(id)
+	tm.auditLocked(ctx, "add", id, nil, after)
+	return nil
+}
 
-	// Add the new truck
+// addLocked adds a validated truck to the fleet. Callers must hold the write lock.
+func (tm *truckManager) addLocked(truck Truck) error {
+	if err := truck.validate(); err != nil {
+		return err
+	}
 
-	tm.trucks[id] = &Truck{
-		ID:    id,
-		Cargo: cargo,
+	// Check if truck already exists
+	if _, exist := tm.trucks[truck.ID]; exist {
+		return ErrTruckExist
+	}
+	custom, err := tm.normalizeFieldsLocked(TruckEntity, truck.Custom)
+	if err != nil {
+		return err
+	}
+	truck.Custom = custom
+	if err := tm.checkLimits(1, truck.Capacity); err != nil {
+		return err
+	}
+	// Routes, like drivers, are not persisted, so a stored truck may name one that is gone
+	if _, exist := tm.routes[truck.RouteID]; !exist {
+		truck.RouteID = ""
+	}
+	if err := tm.checkRouteLocked(truck); err != nil {
+		return err
+	}
+	if truck.Version == 0 {
+		truck.Version = 1
+	}
+	tm.claimDriverLocked(&truck)
+	tm.claimYardLocked(&truck)
+	if err := tm.persist(truck); err != nil {
+		tm.releaseDriverLocked(&truck)
+		tm.releaseYardLocked(&truck)
+		return err
 	}
 
+	// Add the new truck
+	tm.trucks[truck.ID] = newTruckSlot(&truck)
+	tm.insertID(truck.ID)
+	tm.totalCapacity.Add(int64(truck.Capacity))
+	tm.trackIdle(truck.ID, truck.CurrentLoad)
+	tm.tags.add(truck.ID, truck.Tags)
+	tm.geo.move(truck.ID, truck.Location)
+
+	added := truck
+	tm.emit(TruckAdded, truck.ID, nil, &added)
 	return nil
 }
 
 // GetTruck retrieves a truck by its ID
 func (tm *truckManager) GetTruck(id string) (Truck, error) {
+	return tm.GetTruckContext(context.Background(), id)
+}
+
+// GetTruckContext is GetTruck with cancellation
+func (tm *truckManager) GetTruckContext(ctx context.Context, id string) (Truck, error) {
 
 	if id == "" {
 		return Truck{}, ErrEmptyID
 	}
 
-	tm.RLock()
+	if err := tm.rlockContext(ctx); err != nil {
+		return Truck{}, err
+	}
 	defer tm.RUnlock()
 
-	truck, exist := tm.trucks[id]
+	truck, exist := tm.truck(id)
 	if !exist {
 		return Truck{}, ErrTruckNotFound
 	}
 
-	return *truck, nil
+	return truck.clone(), nil
 }
 
-// UpdateTruckCargo updates the cargo capacity of a truck
-func (tm *truckManager) UpdateTruckCargo(id string, cargo int) error {
+// GetTrucks retrieves several trucks in one lock acquisition. Found trucks
+// are returned in the order requested, followed by the IDs that don't exist.
+func (tm *truckManager) GetTrucks(ids []string) ([]Truck, []string, error) {
+	for _, id := range ids {
+		if id == "" {
+			return nil, nil, ErrEmptyID
+		}
+	}
 
-	if id == "" {
-		return ErrEmptyID
+	tm.RLock()
+	defer tm.RUnlock()
+
+	found := make([]Truck, 0, len(ids))
+	var missing []string
+	for _, id := range ids {
+		truck, exist := tm.truck(id)
+		if !exist {
+			missing = append(missing, id)
+			continue
+		}
+		found = append(found, truck.clone())
 	}
-	if cargo < 0 {
+	return found, missing, nil
+}
+
+// UpdateTruckCargo updates the cargo capacity of a truck. The capacity
+// cannot drop below the cargo currently on board.
+func (tm *truckManager) UpdateTruckCargo(id string, capacity int) error {
+	return tm.UpdateTruckCargoContext(context.Background(), id, capacity)
+}
+
+// UpdateTruckCargoContext is UpdateTruckCargo with cancellation
+func (tm *truckManager) UpdateTruckCargoContext(ctx context.Context, id string, capacity int) error {
+	return tm.UpdateTruckContext(ctx, id, NewUpdateSpec().WithCapacity(capacity))
+}
+
+// LoadCargo puts amount more cargo on a truck. It fails with
+// ErrCapacityExceeded if the cargo would not fit.
+func (tm *truckManager) LoadCargo(id string, amount int) error {
+	return tm.LoadCargoContext(context.Background(), id, amount)
+}
+
+// LoadCargoContext is LoadCargo with cancellation
+func (tm *truckManager) LoadCargoContext(ctx context.Context, id string, amount int) error {
+	if amount <= 0 {
 		return ErrInvalidCargo
 	}
+	return tm.moveCargo(ctx, "load", id, amount)
+}
 
-	tm.Lock()
-	defer tm.Unlock()
+// UnloadCargo takes amount of cargo off a truck. It fails with
+// ErrInsufficientCargo if the truck carries less than that.
+func (tm *truckManager) UnloadCargo(id string, amount int) error {
+	return tm.UnloadCargoContext(context.Background(), id, amount)
+}
+
+// UnloadCargoContext is UnloadCargo with cancellation
+func (tm *truckManager) UnloadCargoContext(ctx context.Context, id string, amount int) error {
+	if amount <= 0 {
+		return ErrInvalidCargo
+	}
+	return tm.moveCargo(ctx, "unload", id, -amount)
+}
+
+// moveCargo adds delta to a truck's current load; negative deltas unload.
+// op names the operation in log records.
+func (tm *truckManager) moveCargo(ctx context.Context, op, id string, delta int) (err error) {
+	var before, after *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(ctx, op, id, start, before, after, err) }()
+
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	unlock, err := tm.lockTruckContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	// Check if truck exists
-	truck, exist := tm.trucks[id]
+	truck, exist := tm.truck(id)
 	if !exist {
 		return ErrTruckNotFound
 	}
 
-	truck.Cargo = cargo
+	load := truck.CurrentLoad + delta
+	if load > truck.Capacity {
+		return ErrCapacityExceeded
+	}
+	if load < 0 {
+		return ErrInsufficientCargo
+	}
+	before = tm.snapshotLocked(id)
+	if err := tm.updateLocked(id, NewUpdateSpec().WithCurrentLoad(load)); err != nil {
+		return err
+	}
+	after = tm.snapshotLocked(id)
+	tm.auditLocked(ctx, op, id, before, after)
 	return nil
 }
 
-// RemoveTruck removes a truck from the fleet
+// RemoveTruck removes a truck from the fleet. Unless the recycle bin is
+// off, the truck can be brought back with RestoreTruck until its retention
+// ends; see WithRetention.
 func (tm *truckManager) RemoveTruck(id string) error {
-	tm.Lock()
-	defer tm.Unlock()
+	return tm.RemoveTruckContext(context.Background(), id)
+}
+
+// RemoveTruckContext is RemoveTruck with cancellation
+func (tm *truckManager) RemoveTruckContext(ctx context.Context, id string) (err error) {
+	var before *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(ctx, "remove", id, start, before, nil, err) }()
 
 	if id == "" {
 		return ErrEmptyID
 	}
 
+	if err := tm.lockContext(ctx); err != nil {
+		return err
+	}
+	defer tm.Unlock()
+
+	removed, err := tm.recycleLocked(id)
+	if err != nil {
+		return err
+	}
+	if tm.logger != nil || tm.audit != nil {
+		before = &removed
+	}
+	tm.auditLocked(ctx, "remove", id, before, nil)
+	return nil
+}
+
+// removeLocked removes a truck from the fleet and returns it. Callers must hold the write lock.
+func (tm *truckManager) removeLocked(id string) (Truck, error) {
 	// Check if truck exists
-	if _, exist := tm.trucks[id]; !exist {
-		return ErrTruckNotFound
+	truck, exist := tm.truck(id)
+	if !exist {
+		return Truck{}, ErrTruckNotFound
+	}
+
+	if err := tm.unpersist(id); err != nil {
+		return Truck{}, err
 	}
 
 	delete(tm.trucks, id)
+	tm.totalCapacity.Add(-int64(truck.Capacity))
+	tm.removeID(id)
+	delete(tm.maintenance, id)
+	delete(tm.fuel, id)
+	delete(tm.reservations, id)
+	tm.releaseDriverLocked(truck)
+	tm.releaseYardLocked(truck)
+	tm.cancelAppointmentsLocked(id)
+	tm.tags.remove(id, truck.Tags)
+	tm.geo.move(id, nil)
+
+	removed := *truck
+	tm.emit(TruckRemoved, id, &removed, nil)
+	return removed, nil
+}
+
+// ListTrucks returns up to limit trucks ordered by ID, starting at offset.
+// Only the requested page is copied, so large fleets can be iterated page
+// by page without holding the lock for a copy of the whole map.
+func (tm *truckManager) ListTrucks(offset, limit int) ([]Truck, error) {
+	return tm.ListTrucksContext(context.Background(), offset, limit)
+}
+
+// ListTrucksContext is ListTrucks with cancellation
+func (tm *truckManager) ListTrucksContext(ctx context.Context, offset, limit int) ([]Truck, error) {
+	if offset < 0 || limit <= 0 {
+		return nil, ErrInvalidPage
+	}
+
+	if err := tm.rlockContext(ctx); err != nil {
+		return nil, err
+	}
+	defer tm.RUnlock()
+
+	if offset >= len(tm.ids) {
+		return []Truck{}, nil
+	}
+	end := min(offset+limit, len(tm.ids))
+
+	page := make([]Truck, 0, end-offset)
+	for _, id := range tm.ids[offset:end] {
+		page = append(page, tm.trucks[id].load().clone())
+	}
+	return page, nil
+}
+
+// lockContext takes the write lock unless ctx is done. A mutex wait cannot
+// be interrupted, so ctx is checked again once the lock is held.
+func (tm *truckManager) lockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tm.Lock()
+	if err := ctx.Err(); err != nil {
+		tm.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rlockContext takes the read lock unless ctx is done
+func (tm *truckManager) rlockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tm.RLock()
+	if err := ctx.Err(); err != nil {
+		tm.RUnlock()
+		return err
+	}
 	return nil
 }
 
+// insertID adds id to the sorted ID index. Callers must hold the write lock.
+func (tm *truckManager) insertID(id string) {
+	i, _ := slices.BinarySearch(tm.ids, id)
+	tm.ids = slices.Insert(tm.ids, i, id)
+}
+
+// removeID drops id from the sorted ID index. Callers must hold the write lock.
+func (tm *truckManager) removeID(id string) {
+	if i, found := slices.BinarySearch(tm.ids, id); found {
+		tm.ids = slices.Delete(tm.ids, i, i+1)
+	}
+}
+
 // Main function to demonstrate the usage of FleetManager
 func main() {
+	// Run a subcommand if one was given
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create a new truck manager
-	manager := NewTruckManager()
+	manager := NewFleetManager()
 
 	// Add some trucks
 	err := manager.AddTruck("truck1", 1000)
@@ -155,13 +584,23 @@ func main() {
 	if err != nil {
 		fmt.Printf("Error getting truck1: %v\n", err)
 	} else {
-		fmt.Printf("Found truck: ID=%s, Cargo=%d\n", truck.ID, truck.Cargo)
+		fmt.Printf("Found truck: ID=%s, Capacity=%d, Load=%d\n", truck.ID, truck.Capacity, truck.CurrentLoad)
 	}
 
-	// Update truck cargo
+	// Update truck capacity
 	err = manager.UpdateTruckCargo("truck1", 1500)
 	if err != nil {
-		fmt.Printf("Error updating truck1 cargo: %v\n", err)
+		fmt.Printf("Error updating truck1 capacity: %v\n", err)
+	}
+
+	// Load some cargo, then try to load more than fits
+	err = manager.LoadCargo("truck1", 1200)
+	if err != nil {
+		fmt.Printf("Error loading truck1: %v\n", err)
+	}
+	err = manager.LoadCargo("truck1", 500)
+	if err != nil {
+		fmt.Printf("Expected error when overloading truck1: %v\n", err)
 	}
 
 	// Get the updated truck
@@ -169,7 +608,7 @@ func main() {
 	if err != nil {
 		fmt.Printf("Error getting updated truck1: %v\n", err)
 	} else {
-		fmt.Printf("Updated truck: ID=%s, Cargo=%d\n", truck.ID, truck.Cargo)
+		fmt.Printf("Updated truck: ID=%s, Capacity=%d, Load=%d\n", truck.ID, truck.Capacity, truck.CurrentLoad)
 	}
 
 	// Remove a truck
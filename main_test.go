@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"testing"
+	"time"
 )
 
 func TestAddTruck(t *testing.T) {
@@ -54,8 +57,8 @@ func TestUpdateTruckCargo(t *testing.T) {
 		t.Errorf("Expected no error, got %v", err)
 	}
 
-	if truck.Cargo != 200 {
-		t.Errorf("Expected truck cargo to be 200, got %d", truck.Cargo)
+	if truck.Capacity != 200 {
+		t.Errorf("Expected truck capacity to be 200, got %d", truck.Capacity)
 	}
 }
 
@@ -69,7 +72,7 @@ func TestConcurrentUpdate(t *testing.T) {
 		go func() {
 			for j := 0; j < iterations; j++ {
 				truck, _ := manager.GetTruck("1")
-				manager.UpdateTruckCargo("1", truck.Cargo+1)
+				manager.UpdateTruckCargo("1", truck.Capacity+1)
 			}
 			done <- true
 		}()
@@ -79,3 +82,219 @@ func TestConcurrentUpdate(t *testing.T) {
 		<-done
 	}
 }
+
+func TestListTrucks(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("c", 300)
+	manager.AddTruck("a", 100)
+	manager.AddTruck("d", 400)
+	manager.AddTruck("b", 200)
+
+	page, err := manager.ListTrucks(0, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page) != 3 || page[0].ID != "a" || page[1].ID != "b" || page[2].ID != "c" {
+		t.Errorf("Expected trucks a, b, c, got %v", page)
+	}
+
+	page, _ = manager.ListTrucks(3, 3)
+	if len(page) != 1 || page[0].ID != "d" {
+		t.Errorf("Expected truck d, got %v", page)
+	}
+
+	page, _ = manager.ListTrucks(10, 3)
+	if len(page) != 0 {
+		t.Errorf("Expected empty page, got %v", page)
+	}
+}
+
+func TestListTrucksAfterRemove(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("a", 100)
+	manager.AddTruck("b", 200)
+	manager.AddTruck("c", 300)
+	manager.RemoveTruck("b")
+
+	page, _ := manager.ListTrucks(0, 10)
+	if len(page) != 2 || page[0].ID != "a" || page[1].ID != "c" {
+		t.Errorf("Expected trucks a, c, got %v", page)
+	}
+}
+
+func TestListTrucksInvalidPage(t *testing.T) {
+	manager := NewTruckManager()
+
+	if _, err := manager.ListTrucks(-1, 10); err != ErrInvalidPage {
+		t.Errorf("Expected invalid page error, got %v", err)
+	}
+	if _, err := manager.ListTrucks(0, 0); err != ErrInvalidPage {
+		t.Errorf("Expected invalid page error, got %v", err)
+	}
+}
+
+func TestGetTrucks(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.AddTruck("3", 300)
+
+	found, missing, err := manager.GetTrucks([]string{"3", "4", "1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(found) != 2 || found[0].ID != "3" || found[1].ID != "1" {
+		t.Errorf("Expected trucks 3 and 1 in request order, got %v", found)
+	}
+	if len(missing) != 1 || missing[0] != "4" {
+		t.Errorf("Expected missing [4], got %v", missing)
+	}
+}
+
+func TestGetTrucksEmptyID(t *testing.T) {
+	manager := NewTruckManager()
+
+	if _, _, err := manager.GetTrucks([]string{"1", ""}); err != ErrEmptyID {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+}
+
+func TestLoadCargo(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("1", 1000)
+
+	if err := manager.LoadCargo("1", 600); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.LoadCargo("1", 400); err != nil {
+		t.Fatalf("Expected loading to exactly full to succeed, got %v", err)
+	}
+	if err := manager.LoadCargo("1", 1); err != ErrCapacityExceeded {
+		t.Errorf("Expected capacity exceeded error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 1000 || truck.CurrentLoad != 1000 {
+		t.Errorf("Expected capacity 1000 and load 1000, got %+v", truck)
+	}
+}
+
+func TestUnloadCargo(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("1", 1000)
+	manager.LoadCargo("1", 300)
+
+	if err := manager.UnloadCargo("1", 200); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.UnloadCargo("1", 101); err != ErrInsufficientCargo {
+		t.Errorf("Expected insufficient cargo error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.CurrentLoad != 100 {
+		t.Errorf("Expected load 100, got %d", truck.CurrentLoad)
+	}
+}
+
+func TestMoveCargoErrors(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("1", 1000)
+
+	if err := manager.LoadCargo("1", 0); err != ErrInvalidCargo {
+		t.Errorf("Expected invalid cargo error, got %v", err)
+	}
+	if err := manager.UnloadCargo("1", -5); err != ErrInvalidCargo {
+		t.Errorf("Expected invalid cargo error, got %v", err)
+	}
+	if err := manager.LoadCargo("2", 10); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := manager.LoadCargo("", 10); err != ErrEmptyID {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+}
+
+func TestCapacityBelowLoadRejected(t *testing.T) {
+	manager := NewTruckManager()
+	manager.AddTruck("1", 1000)
+	manager.LoadCargo("1", 800)
+
+	if err := manager.UpdateTruckCargo("1", 500); err != ErrCapacityExceeded {
+		t.Errorf("Expected capacity exceeded error, got %v", err)
+	}
+	if _, err := manager.AddTrucks([]Truck{{ID: "2", Capacity: 10, CurrentLoad: 20}}); err != nil {
+		t.Fatalf("Expected no batch error, got %v", err)
+	}
+	if manager.Exists("2") {
+		t.Errorf("Expected an overloaded truck to be rejected")
+	}
+}
+
+func TestNewFleetManager(t *testing.T) {
+	var manager FleetManager = NewFleetManager(WithCapacity(10))
+	if err := manager.AddTruck("1", 100); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if trucks, _ := manager.ListTrucks(0, 10); len(trucks) != 1 {
+		t.Errorf("Expected 1 truck, got %d", len(trucks))
+	}
+	if err := manager.UpdateTruckCargo("1", 200); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := manager.LoadCargo("1", 150); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if truck, err := manager.GetTruck("1"); err != nil || truck.Capacity != 200 || truck.CurrentLoad != 150 {
+		t.Errorf("Expected capacity 200 and load 150, got %+v, %v", truck, err)
+	}
+	if err := manager.RemoveTruck("1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if _, err := manager.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestNewTruckManager(t *testing.T) {
+	manager := NewTruckManager(WithRetention(0))
+	if err := manager.AddTruck("1", 100); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if truck, err := manager.GetTruck("1"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck 1 with capacity 100, got %+v, %v", truck, err)
+	}
+	if manager.retention != 0 {
+		t.Errorf("Expected options applied, got retention %v", manager.retention)
+	}
+}
+
+func TestNewTruckManagerIgnoresPurgeEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager := NewTruckManager(WithRetention(time.Millisecond), WithPurgeEvery(ctx, time.Millisecond))
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+
+	time.Sleep(20 * time.Millisecond)
+	if removed := manager.ListRemovedTrucks(); len(removed) != 1 {
+		t.Errorf("Expected no purge loop, got %d trucks left in the bin", len(removed))
+	}
+}
+
+func TestWithCapacity(t *testing.T) {
+	manager := NewFleetManager(WithCapacity(50))
+	if cap(manager.ids) != 50 {
+		t.Errorf("Expected room for 50 trucks, got %d", cap(manager.ids))
+	}
+
+	// The hint is not a limit
+	for i := range 60 {
+		if err := manager.AddTruck(fmt.Sprint(i), 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(manager.trucks) != 60 {
+		t.Errorf("Expected 60 trucks, got %d", len(manager.trucks))
+	}
+}
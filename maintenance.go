@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Errors for maintenance scheduling
+var (
+	ErrTruckUnavailable = errors.New("truck unavailable")
+	ErrInvalidWindow    = errors.New("invalid maintenance window")
+	ErrWindowNotFound   = errors.New("maintenance window not found")
+)
+
+// MaintenanceWindow is planned downtime for a truck, covering [Start, End)
+type MaintenanceWindow struct {
+	ID      int
+	TruckID string
+	Start   time.Time
+	End     time.Time
+	Reason  string
+}
+
+// contains reports whether t falls inside the window
+func (w MaintenanceWindow) contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// overlaps reports whether the window shares any time with [start, end)
+func (w MaintenanceWindow) overlaps(start, end time.Time) bool {
+	return w.Start.Before(end) && start.Before(w.End)
+}
+
+// TruckAvailability describes whether a truck can be dispatched at a point in time
+type TruckAvailability struct {
+	Available bool
+	Current   *MaintenanceWindow  // the window in effect, if any
+	Upcoming  []MaintenanceWindow // windows starting later, earliest first
+	// Reservation is the reservation holding the truck, if any
+	Reservation *Reservation
+}
+
+// ScheduleMaintenance declares planned downtime for a truck. While a window
+// is in effect, selection skips the truck and CheckAvailable rejects it with
+// ErrTruckUnavailable.
+func (tm *truckManager) ScheduleMaintenance(truckID string, start, end time.Time, reason string) (MaintenanceWindow, error) {
+	if truckID == "" {
+		return MaintenanceWindow{}, ErrEmptyID
+	}
+	if !end.After(start) {
+		return MaintenanceWindow{}, ErrInvalidWindow
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.trucks[truckID]; !exist {
+		return MaintenanceWindow{}, ErrTruckNotFound
+	}
+
+	tm.nextWindowID++
+	w := MaintenanceWindow{ID: tm.nextWindowID, TruckID: truckID, Start: start, End: end, Reason: reason}
+
+	// Drop windows that have already ended while we're here
+	now := time.Now()
+	kept := tm.maintenance[truckID][:0]
+	for _, existing := range tm.maintenance[truckID] {
+		if existing.End.After(now) {
+			kept = append(kept, existing)
+		}
+	}
+	kept = append(kept, w)
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start.Before(kept[j].Start) })
+	tm.maintenance[truckID] = kept
+
+	return w, nil
+}
+
+// CancelMaintenance removes a scheduled window
+func (tm *truckManager) CancelMaintenance(windowID int) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	for truckID, windows := range tm.maintenance {
+		for i, w := range windows {
+			if w.ID == windowID {
+				tm.maintenance[truckID] = append(windows[:i], windows[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return ErrWindowNotFound
+}
+
+// Availability reports whether the truck is available at the given time,
+// along with the window in effect and any upcoming windows
+func (tm *truckManager) Availability(truckID string, at time.Time) (TruckAvailability, error) {
+	if truckID == "" {
+		return TruckAvailability{}, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if _, exist := tm.trucks[truckID]; !exist {
+		return TruckAvailability{}, ErrTruckNotFound
+	}
+
+	availability := TruckAvailability{Available: true}
+	for _, w := range tm.maintenance[truckID] {
+		switch {
+		case w.contains(at):
+			current := w
+			availability.Current = &current
+			availability.Available = false
+		case w.Start.After(at):
+			availability.Upcoming = append(availability.Upcoming, w)
+		}
+	}
+	if r, exist := tm.reservations[truckID]; exist && r.active(at) {
+		availability.Reservation = &r
+		availability.Available = false
+	}
+	return availability, nil
+}
+
+// CheckAvailable returns an error wrapping ErrTruckUnavailable if the truck
+// is under maintenance or reserved at the given time
+func (tm *truckManager) CheckAvailable(truckID string, at time.Time) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if _, exist := tm.trucks[truckID]; !exist {
+		return ErrTruckNotFound
+	}
+	if err := tm.checkAvailableLocked(truckID, at); err != nil {
+		return err
+	}
+	return tm.checkReservedLocked(truckID, at)
+}
+
+// checkAvailableLocked is CheckAvailable for callers already holding the lock
+func (tm *truckManager) checkAvailableLocked(truckID string, at time.Time) error {
+	for _, w := range tm.maintenance[truckID] {
+		if w.contains(at) {
+			return fmt.Errorf("%w: %s is in maintenance until %s", ErrTruckUnavailable, truckID, w.End.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestScheduleMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+
+	w, err := manager.ScheduleMaintenance("1", now.Add(-time.Hour), now.Add(time.Hour), "brakes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if w.ID == 0 || w.TruckID != "1" {
+		t.Errorf("Expected a numbered window for truck 1, got %+v", w)
+	}
+
+	if err := manager.CheckAvailable("1", now); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected truck unavailable error, got %v", err)
+	}
+	if err := manager.CheckAvailable("1", now.Add(2*time.Hour)); err != nil {
+		t.Errorf("Expected truck to be available after the window, got %v", err)
+	}
+}
+
+func TestScheduleMaintenanceErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+
+	if _, err := manager.ScheduleMaintenance("1", now, now, ""); err != ErrInvalidWindow {
+		t.Errorf("Expected invalid window error, got %v", err)
+	}
+	if _, err := manager.ScheduleMaintenance("2", now, now.Add(time.Hour), ""); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := manager.CancelMaintenance(42); err != ErrWindowNotFound {
+		t.Errorf("Expected window not found error, got %v", err)
+	}
+}
+
+func TestAvailability(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+
+	manager.ScheduleMaintenance("1", now.Add(48*time.Hour), now.Add(50*time.Hour), "inspection")
+	manager.ScheduleMaintenance("1", now.Add(24*time.Hour), now.Add(26*time.Hour), "tyres")
+
+	availability, err := manager.Availability("1", now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !availability.Available || availability.Current != nil {
+		t.Errorf("Expected truck to be available now, got %+v", availability)
+	}
+	if len(availability.Upcoming) != 2 || availability.Upcoming[0].Reason != "tyres" {
+		t.Errorf("Expected two upcoming windows, earliest first, got %+v", availability.Upcoming)
+	}
+
+	availability, _ = manager.Availability("1", now.Add(25*time.Hour))
+	if availability.Available || availability.Current == nil || availability.Current.Reason != "tyres" {
+		t.Errorf("Expected the tyres window to be in effect, got %+v", availability)
+	}
+}
+
+func TestCancelMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+
+	w, _ := manager.ScheduleMaintenance("1", now.Add(-time.Hour), now.Add(time.Hour), "")
+	if err := manager.CancelMaintenance(w.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.CheckAvailable("1", now); err != nil {
+		t.Errorf("Expected truck to be available after cancelling, got %v", err)
+	}
+}
+
+func TestSelectionSkipsMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	now := time.Now()
+	manager.ScheduleMaintenance("1", now.Add(-time.Hour), now.Add(time.Hour), "")
+
+	truck, err := manager.SelectTruck(SelectLeastLoaded, nil)
+	if err != nil || truck.ID != "2" {
+		t.Errorf("Expected truck 2, got %+v, %v", truck, err)
+	}
+
+	balancer := NewLaneBalancer(manager, []string{"1", "2"})
+	for i := 0; i < 3; i++ {
+		if truck, _ := balancer.Next(); truck.ID != "2" {
+			t.Errorf("Expected balancer to skip truck 1, got %s", truck.ID)
+		}
+	}
+}
+
+func TestRemoveTruckDropsMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+	manager.ScheduleMaintenance("1", now.Add(-time.Hour), now.Add(time.Hour), "")
+
+	manager.RemoveTruck("1")
+	manager.AddTruck("1", 100)
+
+	if err := manager.CheckAvailable("1", now); err != nil {
+		t.Errorf("Expected a re-added truck to have no windows, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMessageNotFound is returned for an unknown message ID, or one that
+// belongs to another driver
+var ErrMessageNotFound = errors.New("message not found")
+
+// MessageKind says what a message asks of the driver
+type MessageKind string
+
+// Message kinds
+const (
+	MessageNote         MessageKind = "note"
+	MessageNewStop      MessageKind = "new_stop"
+	MessageRouteChanged MessageKind = "route_changed"
+	MessageCallOffice   MessageKind = "call_office"
+)
+
+// Message is a message or task from dispatch to a driver. DeliveredAt and
+// ReadAt are the receipts; they are zero until the driver's inbox is
+// fetched and the message is read.
+type Message struct {
+	ID          int
+	DriverID    string
+	Kind        MessageKind
+	Body        string
+	SentAt      time.Time
+	DeliveredAt time.Time
+	ReadAt      time.Time
+}
+
+// SendMessage queues a message in a driver's inbox. Messages are held in
+// memory only and are discarded with the driver.
+func (tm *truckManager) SendMessage(driverID string, kind MessageKind, body string) (Message, error) {
+	if driverID == "" {
+		return Message{}, ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.drivers[driverID]; !exist {
+		return Message{}, ErrDriverNotFound
+	}
+	return tm.sendLocked(driverID, kind, body), nil
+}
+
+// FetchInbox returns a driver's messages, oldest first, and marks the ones
+// not yet delivered as delivered. It is what a driver's device calls.
+func (tm *truckManager) FetchInbox(driverID string) ([]Message, error) {
+	if driverID == "" {
+		return nil, ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.drivers[driverID]; !exist {
+		return nil, ErrDriverNotFound
+	}
+
+	now := time.Now()
+	inbox := make([]Message, 0, len(tm.inboxes[driverID]))
+	for _, m := range tm.inboxes[driverID] {
+		if m.DeliveredAt.IsZero() {
+			m.DeliveredAt = now
+		}
+		inbox = append(inbox, *m)
+	}
+	return inbox, nil
+}
+
+// MarkRead records that the driver has read a message. Reading implies
+// delivery, so an undelivered message is marked delivered too.
+func (tm *truckManager) MarkRead(driverID string, messageID int) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	m, exist := tm.messages[messageID]
+	if !exist || m.DriverID != driverID {
+		return ErrMessageNotFound
+	}
+	if !m.ReadAt.IsZero() {
+		return nil
+	}
+	now := time.Now()
+	if m.DeliveredAt.IsZero() {
+		m.DeliveredAt = now
+	}
+	m.ReadAt = now
+	return nil
+}
+
+// GetMessage returns a message with its current receipts, for dispatch to
+// check whether it has been delivered and read
+func (tm *truckManager) GetMessage(messageID int) (Message, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	m, exist := tm.messages[messageID]
+	if !exist {
+		return Message{}, ErrMessageNotFound
+	}
+	return *m, nil
+}
+
+// sendLocked queues a message for an existing driver. Callers must hold the write lock.
+func (tm *truckManager) sendLocked(driverID string, kind MessageKind, body string) Message {
+	tm.nextMessageID++
+	m := &Message{ID: tm.nextMessageID, DriverID: driverID, Kind: kind, Body: body, SentAt: time.Now()}
+	tm.messages[m.ID] = m
+	tm.inboxes[driverID] = append(tm.inboxes[driverID], m)
+	return *m
+}
+
+// dropInboxLocked discards a driver's messages. Callers must hold the write lock.
+func (tm *truckManager) dropInboxLocked(driverID string) {
+	for _, m := range tm.inboxes[driverID] {
+		delete(tm.messages, m.ID)
+	}
+	delete(tm.inboxes, driverID)
+}
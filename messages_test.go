@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSendMessage(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddDriver("d1", "Amina")
+
+	sent, err := manager.SendMessage("d1", MessageCallOffice, "Call dispatch when you can")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent.ID == 0 || sent.SentAt.IsZero() || !sent.DeliveredAt.IsZero() {
+		t.Errorf("Expected a numbered, sent, undelivered message, got %+v", sent)
+	}
+	if _, err := manager.SendMessage("d2", MessageNote, "hi"); err != ErrDriverNotFound {
+		t.Errorf("Expected driver not found error, got %v", err)
+	}
+}
+
+func TestMessageReceipts(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddDriver("d1", "Amina")
+	manager.AddDriver("d2", "Ben")
+	sent, _ := manager.SendMessage("d1", MessageNewStop, "Extra pickup at Gate 4")
+
+	inbox, err := manager.FetchInbox("d1")
+	if err != nil || len(inbox) != 1 {
+		t.Fatalf("Expected one message, got %+v, %v", inbox, err)
+	}
+	if inbox[0].DeliveredAt.IsZero() || !inbox[0].ReadAt.IsZero() {
+		t.Errorf("Expected the fetched message delivered but unread, got %+v", inbox[0])
+	}
+
+	if err := manager.MarkRead("d2", sent.ID); err != ErrMessageNotFound {
+		t.Errorf("Expected another driver's message to be hidden, got %v", err)
+	}
+	if err := manager.MarkRead("d1", sent.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, _ := manager.GetMessage(sent.ID)
+	if got.ReadAt.IsZero() {
+		t.Errorf("Expected a read receipt, got %+v", got)
+	}
+}
+
+func TestRouteChangeNotifiesDriver(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	manager.AssignDriver("1", "d1")
+	manager.CreateRoute(Route{ID: "r1", Origin: "A", Destination: "B"})
+
+	manager.AssignRoute("1", "r1")
+	inbox, _ := manager.FetchInbox("d1")
+	if len(inbox) != 1 || inbox[0].Kind != MessageRouteChanged {
+		t.Errorf("Expected a route changed message, got %+v", inbox)
+	}
+}
+
+func TestRemoveDriverDropsInbox(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddDriver("d1", "Amina")
+	sent, _ := manager.SendMessage("d1", MessageNote, "hi")
+
+	manager.RemoveDriver("d1")
+	if _, err := manager.GetMessage(sent.ID); err != ErrMessageNotFound {
+		t.Errorf("Expected the message to be discarded, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the operation latency
+// histogram. Mutations are in-memory and usually finish in microseconds; the
+// upper buckets catch slow storage.
+var latencyBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}
+
+// errorKinds maps the errors worth telling apart to their metric label.
+// Anything else is counted as "other".
+var errorKinds = []struct {
+	err  error
+	kind string
+}{
+	{ErrTruckNotFound, "not_found"},
+	{ErrTruckExist, "exists"},
+	{ErrEmptyID, "empty_id"},
+	{ErrInvalidCargo, "invalid_cargo"},
+	{ErrCapacityExceeded, "capacity_exceeded"},
+	{ErrInsufficientCargo, "insufficient_cargo"},
+	{ErrFleetLimitReached, "limit_reached"},
+	{ErrRouteWeightLimit, "route_weight_limit"},
+	{ErrVersionConflict, "version_conflict"},
+	{context.Canceled, "canceled"},
+	{context.DeadlineExceeded, "deadline_exceeded"},
+}
+
+// errorKind returns the metric label for err
+func errorKind(err error) string {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.kind
+		}
+	}
+	return "other"
+}
+
+// Metrics counts fleet operations for export through the manager's
+// Collector. It is safe for concurrent use.
+type Metrics struct {
+	mu  sync.Mutex
+	ops map[string]*opMetrics
+}
+
+// opMetrics holds the counters for one operation
+type opMetrics struct {
+	count   int
+	errors  map[string]int
+	buckets []int // cumulative counts per latencyBuckets entry
+	sum     float64
+	timed   int // observations included in the histogram
+}
+
+// NewMetrics creates an empty set of operation metrics
+func NewMetrics() *Metrics {
+	return &Metrics{ops: make(map[string]*opMetrics)}
+}
+
+// WithMetrics counts every mutation into m. Fleet size, capacity and load
+// gauges are read from the manager when metrics are written.
+func WithMetrics(m *Metrics) Option {
+	return func(o *managerOptions) {
+		o.metrics = m
+	}
+}
+
+// op returns the counters for name, creating them. Callers must hold m.mu.
+func (m *Metrics) op(name string) *opMetrics {
+	o, exist := m.ops[name]
+	if !exist {
+		o = &opMetrics{errors: make(map[string]int), buckets: make([]int, len(latencyBuckets))}
+		m.ops[name] = o
+	}
+	return o
+}
+
+// observe counts one operation that took d and failed with err, if set
+func (m *Metrics) observe(name string, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.op(name)
+	o.count++
+	if err != nil {
+		o.errors[errorKind(err)]++
+	}
+	seconds := d.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			o.buckets[i]++
+		}
+	}
+	o.sum += seconds
+	o.timed++
+}
+
+// count counts one operation, with err if set, without timing it
+func (m *Metrics) count(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	o := m.op(name)
+	o.count++
+	if err != nil {
+		o.errors[errorKind(err)]++
+	}
+}
+
+// recordMutation logs and counts the outcome of one mutation
+func (tm *truckManager) recordMutation(ctx context.Context, op, id string, start time.Time, before, after *Truck, err error) {
+	tm.logMutation(ctx, op, id, before, after, err)
+	if tm.metrics != nil {
+		tm.metrics.observe(op, time.Since(start), err)
+	}
+}
+
+// recordBatch logs and counts the outcome of every item in a finished batch.
+// Items share one lock acquisition, so they are counted but not timed.
+func (tm *truckManager) recordBatch(op string, result BatchResult) {
+	tm.logBatch(op, result)
+	if tm.metrics == nil {
+		return
+	}
+	for range result.Succeeded {
+		tm.metrics.count(op, nil)
+	}
+	for _, failed := range result.Failed {
+		tm.metrics.count(op, failed.Err)
+	}
+}
+
+// Descriptions of the exported series
+var (
+	trucksDesc     = prometheus.NewDesc("fleet_trucks", "Number of trucks in the fleet.", nil, nil)
+	capacityDesc   = prometheus.NewDesc("fleet_capacity_total", "Sum of cargo capacity across the fleet.", nil, nil)
+	loadDesc       = prometheus.NewDesc("fleet_load_total", "Sum of cargo currently on board across the fleet.", nil, nil)
+	operationsDesc = prometheus.NewDesc("fleet_operations_total", "Fleet mutations attempted, by operation.", []string{"op"}, nil)
+	errorsDesc     = prometheus.NewDesc("fleet_operation_errors_total", "Failed fleet mutations, by operation and error.", []string{"op", "error"}, nil)
+	durationDesc   = prometheus.NewDesc("fleet_operation_duration_seconds", "Latency of single-truck fleet mutations.", []string{"op"}, nil)
+)
+
+// Collector returns a prometheus.Collector for the fleet gauges and, when
+// WithMetrics is configured, the operation counters and latency histograms.
+// Register it with a prometheus.Registry to export the fleet alongside other
+// metrics.
+func (tm *truckManager) Collector() prometheus.Collector {
+	return fleetCollector{tm}
+}
+
+// fleetCollector reads the manager's metrics each time it is collected
+type fleetCollector struct {
+	tm *truckManager
+}
+
+// Describe sends the descriptions of every series the collector exports
+func (c fleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{trucksDesc, capacityDesc, loadDesc, operationsDesc, errorsDesc, durationDesc} {
+		ch <- d
+	}
+}
+
+// Collect sends the current value of every series
+func (c fleetCollector) Collect(ch chan<- prometheus.Metric) {
+	tm := c.tm
+	tm.RLock()
+	trucks := len(tm.trucks)
+	capacity := tm.totalCapacity.Load()
+	load := 0
+	for _, slot := range tm.trucks {
+		load += slot.load().CurrentLoad
+	}
+	tm.RUnlock()
+
+	ch <- prometheus.MustNewConstMetric(trucksDesc, prometheus.GaugeValue, float64(trucks))
+	ch <- prometheus.MustNewConstMetric(capacityDesc, prometheus.GaugeValue, float64(capacity))
+	ch <- prometheus.MustNewConstMetric(loadDesc, prometheus.GaugeValue, float64(load))
+	if tm.metrics != nil {
+		tm.metrics.collect(ch)
+	}
+}
+
+// collect sends the operation counters and histograms
+func (m *Metrics) collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, o := range m.ops {
+		ch <- prometheus.MustNewConstMetric(operationsDesc, prometheus.CounterValue, float64(o.count), name)
+		for kind, n := range o.errors {
+			ch <- prometheus.MustNewConstMetric(errorsDesc, prometheus.CounterValue, float64(n), name, kind)
+		}
+		if o.timed == 0 {
+			continue
+		}
+		buckets := make(map[float64]uint64, len(latencyBuckets))
+		for i, bound := range latencyBuckets {
+			buckets[bound] = uint64(o.buckets[i])
+		}
+		ch <- prometheus.MustNewConstHistogram(durationDesc, uint64(o.timed), o.sum, buckets, name)
+	}
+}
+
+// registry returns a registry holding only the fleet collector
+func (tm *truckManager) registry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(tm.Collector())
+	return reg
+}
+
+// WriteMetrics writes what Collector exports in the Prometheus text
+// exposition format
+func (tm *truckManager) WriteMetrics(w io.Writer) error {
+	families, err := tm.registry().Gather()
+	if err != nil {
+		return err
+	}
+	enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetricsHandler serves what Collector exports, for mounting at /metrics.
+// To serve the fleet together with other metrics, register Collector with
+// your own registry and serve that with promhttp instead.
+func (tm *truckManager) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(tm.registry(), promhttp.HandlerOpts{})
+}
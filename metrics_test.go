@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	manager := NewFleetManager(WithMetrics(NewMetrics()))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 300)
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("2", 50)
+	manager.LoadCargo("2", 1000)
+	manager.RemoveTruck("3")
+
+	var buf bytes.Buffer
+	if err := manager.WriteMetrics(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range []string{
+		"fleet_trucks 2",
+		"fleet_capacity_total 400",
+		"fleet_load_total 50",
+		`fleet_operations_total{op="add"} 3`,
+		`fleet_operations_total{op="load"} 2`,
+		`fleet_operation_errors_total{error="exists",op="add"} 1`,
+		`fleet_operation_errors_total{error="capacity_exceeded",op="load"} 1`,
+		`fleet_operation_errors_total{error="not_found",op="remove"} 1`,
+		`fleet_operation_duration_seconds_bucket{op="add",le="+Inf"} 3`,
+		`fleet_operation_duration_seconds_count{op="remove"} 1`,
+		"# TYPE fleet_operation_duration_seconds histogram",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, out)
+		}
+	}
+}
+
+func TestWriteMetricsCountsBatches(t *testing.T) {
+	manager := NewFleetManager(WithMetrics(NewMetrics()))
+	manager.AddTrucks([]Truck{{ID: "1", Capacity: 100}, {ID: "1", Capacity: 100}})
+
+	var buf bytes.Buffer
+	manager.WriteMetrics(&buf)
+	out := buf.String()
+	if !strings.Contains(out, `fleet_operations_total{op="add"} 2`) || !strings.Contains(out, `fleet_operation_errors_total{error="exists",op="add"} 1`) {
+		t.Errorf("Expected batch items to be counted, got:\n%s", out)
+	}
+	if strings.Contains(out, "fleet_operation_duration_seconds_count") {
+		t.Errorf("Expected batch items not to be timed, got:\n%s", out)
+	}
+}
+
+func TestCollectorRegisters(t *testing.T) {
+	manager := NewFleetManager(WithMetrics(NewMetrics()))
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("1", 500)
+
+	// The pedantic registry also checks the collected series against Describe
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(manager.Collector()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	values := make(map[string]float64)
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			switch {
+			case m.GetGauge() != nil:
+				values[mf.GetName()] = m.GetGauge().GetValue()
+			case m.GetCounter() != nil:
+				values[mf.GetName()] += m.GetCounter().GetValue()
+			}
+		}
+	}
+	if values["fleet_trucks"] != 1 || values["fleet_capacity_total"] != 100 {
+		t.Errorf("Expected 1 truck with capacity 100, got %v", values)
+	}
+	if values["fleet_operations_total"] != 2 || values["fleet_operation_errors_total"] != 1 {
+		t.Errorf("Expected 2 operations and 1 error, got %v", values)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	rec := httptest.NewRecorder()
+	manager.MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected text/plain, got %q", rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "fleet_trucks 1\n") {
+		t.Errorf("Expected fleet size gauge, got:\n%s", body)
+	}
+	if strings.Contains(body, "fleet_operations_total") {
+		t.Errorf("Expected no operation counters without WithMetrics, got:\n%s", body)
+	}
+}
+
+func TestWriteMetricsCountsEveryMutation(t *testing.T) {
+	manager := NewFleetManager(WithMetrics(NewMetrics()))
+	manager.CreateTruck(100)
+	manager.UpsertTruck("2", NewUpdateSpec().WithCapacity(200))
+	manager.UpsertTruck("2", NewUpdateSpec().WithCapacity(300))
+	manager.RemoveTruck("2")
+	manager.RestoreTruck("2")
+	manager.RemoveTruck("2")
+	manager.PurgeTruck("2")
+	manager.PurgeTruck("2")
+	manager.ImportFleet(strings.NewReader("id,capacity,current_load\n3,100,0\n4,-1,0\n"), FormatCSV, MergeReplace)
+
+	var buf bytes.Buffer
+	manager.WriteMetrics(&buf)
+	out := buf.String()
+	for _, line := range []string{
+		`fleet_operations_total{op="add"} 2`,
+		`fleet_operations_total{op="update"} 1`,
+		`fleet_operations_total{op="restore"} 1`,
+		`fleet_operations_total{op="purge"} 2`,
+		`fleet_operation_errors_total{error="other",op="purge"} 1`,
+		`fleet_operations_total{op="import"} 3`,
+		`fleet_operation_duration_seconds_count{op="restore"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors for long-running operations
+var (
+	ErrOperationNotFound = errors.New("operation not found")
+	ErrOperationDone     = errors.New("operation already finished")
+)
+
+// OperationState is where an operation is in its lifecycle
+type OperationState string
+
+// Operation states. Running is the only state an operation leaves.
+const (
+	OperationRunning   OperationState = "running"
+	OperationSucceeded OperationState = "succeeded"
+	OperationFailed    OperationState = "failed"
+	OperationCanceled  OperationState = "canceled"
+)
+
+// errInterrupted is recorded for operations that were running when the process stopped
+const errInterrupted = "interrupted: the process stopped while the operation was running"
+
+// progressSaveInterval bounds how often progress alone rewrites the records file
+const progressSaveInterval = time.Second
+
+// Operation is the record of one long-running operation
+type Operation struct {
+	ID    string         `json:"id"`
+	Kind  string         `json:"kind"`
+	State OperationState `json:"state"`
+	// Done and Total measure progress in units of the operation's choosing,
+	// such as rows; Total is 0 while unknown
+	Done     int       `json:"done"`
+	Total    int       `json:"total"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitzero"`
+	// Result is the operation's outcome as JSON, such as an ImportReport
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// Progress lets a running operation report how far it has got
+type Progress struct {
+	ops *Operations
+	id  string
+}
+
+// Report records that done of total units are finished
+func (p *Progress) Report(done, total int) {
+	p.ops.report(p.id, done, total)
+}
+
+// OperationFunc is the body of an operation. It should return soon after
+// ctx is canceled. The result is stored as JSON in the operation record.
+type OperationFunc func(ctx context.Context, p *Progress) (result any, err error)
+
+// Operations runs work in the background and keeps a record of each run,
+// so a caller can start an operation, poll its progress and cancel it
+// instead of waiting for it. It is safe for concurrent use.
+type Operations struct {
+	mu     sync.Mutex
+	ops    map[string]*operation
+	nextID int
+	// path is the file records are saved to; empty keeps them in memory only
+	path      string
+	lastSaved time.Time
+	err       error
+}
+
+// operation is a record together with the handles of its run
+type operation struct {
+	rec    Operation
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOperations returns an operation registry. If path is not empty the
+// records are saved there as JSON and the records of earlier runs are read
+// back; an operation that was still running is marked failed, since its
+// work stopped with the process.
+func NewOperations(path string) (*Operations, error) {
+	o := &Operations{ops: make(map[string]*operation), path: path}
+	if path == "" {
+		return o, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []Operation
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("reading operations: %w", err)
+	}
+	for _, rec := range records {
+		if rec.State == OperationRunning {
+			rec.State = OperationFailed
+			rec.Error = errInterrupted
+		}
+		done := make(chan struct{})
+		close(done)
+		o.ops[rec.ID] = &operation{rec: rec, done: done}
+		if n, err := strconv.Atoi(strings.TrimPrefix(rec.ID, "op-")); err == nil && n > o.nextID {
+			o.nextID = n
+		}
+	}
+	return o, nil
+}
+
+// Start runs fn in a new goroutine and returns the ID of its record
+func (o *Operations) Start(kind string, fn OperationFunc) string {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	o.mu.Lock()
+	o.nextID++
+	id := "op-" + strconv.Itoa(o.nextID)
+	op := &operation{
+		rec:    Operation{ID: id, Kind: kind, State: OperationRunning, Started: time.Now()},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	o.ops[id] = op
+	o.saveLocked()
+	o.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		result, err := fn(ctx, &Progress{ops: o, id: id})
+		o.finish(op, ctx, result, err)
+	}()
+	return id
+}
+
+// finish records the outcome of a run and wakes its waiters
+func (o *Operations) finish(op *operation, ctx context.Context, result any, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	switch {
+	case err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		op.rec.State = OperationCanceled
+	case err != nil:
+		op.rec.State = OperationFailed
+	default:
+		op.rec.State = OperationSucceeded
+	}
+	if err != nil {
+		op.rec.Error = err.Error()
+	}
+	if result != nil {
+		if data, merr := json.Marshal(result); merr == nil {
+			op.rec.Result = data
+		}
+	}
+	op.rec.Finished = time.Now()
+	o.saveLocked()
+	close(op.done)
+}
+
+// report updates the progress of a running operation
+func (o *Operations) report(id string, done, total int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op := o.ops[id]
+	if op.rec.State != OperationRunning {
+		return
+	}
+	op.rec.Done, op.rec.Total = done, total
+	if time.Since(o.lastSaved) >= progressSaveInterval {
+		o.saveLocked()
+	}
+}
+
+// Status returns the record of an operation
+func (o *Operations) Status(id string) (Operation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op, exist := o.ops[id]
+	if !exist {
+		return Operation{}, ErrOperationNotFound
+	}
+	return op.rec, nil
+}
+
+// List returns every operation record, newest first
+func (o *Operations) List() []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	records := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		records = append(records, op.rec)
+	}
+	slices.SortFunc(records, func(a, b Operation) int { return b.Started.Compare(a.Started) })
+	return records
+}
+
+// Cancel asks a running operation to stop. The operation is marked
+// canceled once its function returns; use Wait to see the final record.
+func (o *Operations) Cancel(id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op, exist := o.ops[id]
+	if !exist {
+		return ErrOperationNotFound
+	}
+	if op.rec.State != OperationRunning {
+		return ErrOperationDone
+	}
+	op.cancel()
+	return nil
+}
+
+// Wait blocks until the operation finishes or ctx is done, and returns its record
+func (o *Operations) Wait(ctx context.Context, id string) (Operation, error) {
+	o.mu.Lock()
+	op, exist := o.ops[id]
+	o.mu.Unlock()
+	if !exist {
+		return Operation{}, ErrOperationNotFound
+	}
+
+	select {
+	case <-op.done:
+	case <-ctx.Done():
+		return Operation{}, ctx.Err()
+	}
+	return o.Status(id)
+}
+
+// Err returns the first error encountered while saving the records
+func (o *Operations) Err() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.err
+}
+
+// saveLocked writes every record to the records file. A failed save must
+// not fail the operation, so the error is kept for Err. Callers must hold mu.
+func (o *Operations) saveLocked() {
+	if o.path == "" {
+		return
+	}
+	records := make([]Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		records = append(records, op.rec)
+	}
+	slices.SortFunc(records, func(a, b Operation) int { return a.Started.Compare(b.Started) })
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(o.path, data)
+	}
+	if err != nil && o.err == nil {
+		o.err = err
+	}
+	o.lastSaved = time.Now()
+}
+
+// StartImport reads trucks from r and imports them as a background
+// operation, reporting progress in rows. Unlike ImportFleet, rows are
+// applied in chunks, so the fleet stays usable during a large import and a
+// cancel stops it between chunks, keeping the rows already applied. Trucks
+// a replace import leaves out are removed with the last chunk.
+// Input that cannot be read fails before the operation starts.
+func (tm *truckManager) StartImport(ops *Operations, r io.Reader, format Format, mode MergeMode) (string, error) {
+	rows, err := readImportRows(r, format, mode)
+	if err != nil {
+		return "", err
+	}
+	return ops.Start("import", func(ctx context.Context, p *Progress) (any, error) {
+		return tm.importRows(ctx, rows, mode, importChunkSize, p.Report)
+	}), nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOperationSucceeds(t *testing.T) {
+	ops, _ := NewOperations("")
+	id := ops.Start("count", func(ctx context.Context, p *Progress) (any, error) {
+		p.Report(1, 2)
+		p.Report(2, 2)
+		return map[string]int{"counted": 2}, nil
+	})
+
+	op, err := ops.Wait(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if op.State != OperationSucceeded || op.Kind != "count" {
+		t.Errorf("Expected succeeded count operation, got %+v", op)
+	}
+	if op.Done != 2 || op.Total != 2 {
+		t.Errorf("Expected progress 2 of 2, got %d of %d", op.Done, op.Total)
+	}
+	if string(op.Result) != `{"counted":2}` {
+		t.Errorf("Expected result recorded, got %s", op.Result)
+	}
+	if op.Finished.IsZero() {
+		t.Errorf("Expected finish time set")
+	}
+}
+
+func TestOperationFails(t *testing.T) {
+	ops, _ := NewOperations("")
+	id := ops.Start("fail", func(ctx context.Context, p *Progress) (any, error) {
+		return nil, errors.New("disk full")
+	})
+
+	op, _ := ops.Wait(context.Background(), id)
+	if op.State != OperationFailed || op.Error != "disk full" {
+		t.Errorf("Expected failed operation, got %+v", op)
+	}
+	if err := ops.Cancel(id); !errors.Is(err, ErrOperationDone) {
+		t.Errorf("Expected operation done error, got %v", err)
+	}
+}
+
+func TestOperationCancel(t *testing.T) {
+	ops, _ := NewOperations("")
+	started := make(chan struct{})
+	id := ops.Start("wait", func(ctx context.Context, p *Progress) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	if op, _ := ops.Status(id); op.State != OperationRunning {
+		t.Errorf("Expected running operation, got %s", op.State)
+	}
+	if err := ops.Cancel(id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	op, _ := ops.Wait(context.Background(), id)
+	if op.State != OperationCanceled {
+		t.Errorf("Expected canceled operation, got %s", op.State)
+	}
+}
+
+func TestOperationNotFound(t *testing.T) {
+	ops, _ := NewOperations("")
+	if _, err := ops.Status("op-9"); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("Expected operation not found error, got %v", err)
+	}
+	if err := ops.Cancel("op-9"); !errors.Is(err, ErrOperationNotFound) {
+		t.Errorf("Expected operation not found error, got %v", err)
+	}
+}
+
+func TestOperationsPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "operations.json")
+	ops, err := NewOperations(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	id := ops.Start("count", func(ctx context.Context, p *Progress) (any, error) { return nil, nil })
+	ops.Wait(context.Background(), id)
+	if err := ops.Err(); err != nil {
+		t.Fatalf("Expected no save error, got %v", err)
+	}
+
+	// A record left running by a stopped process comes back failed
+	data, _ := os.ReadFile(path)
+	var records []Operation
+	json.Unmarshal(data, &records)
+	records = append(records, Operation{ID: "op-7", Kind: "import", State: OperationRunning, Started: time.Now()})
+	data, _ = json.Marshal(records)
+	os.WriteFile(path, data, 0o644)
+
+	reopened, err := NewOperations(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if op, err := reopened.Status(id); err != nil || op.State != OperationSucceeded {
+		t.Errorf("Expected earlier operation restored, got %+v, %v", op, err)
+	}
+	if op, _ := reopened.Status("op-7"); op.State != OperationFailed || op.Error != errInterrupted {
+		t.Errorf("Expected interrupted operation marked failed, got %+v", op)
+	}
+	next := reopened.Start("count", func(ctx context.Context, p *Progress) (any, error) { return nil, nil })
+	if next != "op-8" {
+		t.Errorf("Expected IDs to continue after op-7, got %s", next)
+	}
+	// Let the final save finish before the directory is cleaned up
+	reopened.Wait(context.Background(), next)
+	if list := reopened.List(); len(list) != 3 {
+		t.Errorf("Expected 3 operations, got %d", len(list))
+	}
+}
+
+func TestStartImport(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("id,capacity\n")
+	for i := range importChunkSize + 10 {
+		fmt.Fprintf(&b, "%d,100\n", i)
+	}
+
+	manager := NewFleetManager()
+	manager.AddTruck("stale", 100)
+	ops, _ := NewOperations("")
+	id, err := manager.StartImport(ops, strings.NewReader(b.String()), FormatCSV, MergeReplace)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	op, _ := ops.Wait(context.Background(), id)
+	if op.State != OperationSucceeded || op.Done != importChunkSize+10 || op.Total != importChunkSize+10 {
+		t.Errorf("Expected finished import of every row, got %+v", op)
+	}
+	var report ImportReport
+	json.Unmarshal(op.Result, &report)
+	if report.Imported != importChunkSize+10 || report.Removed != 1 {
+		t.Errorf("Expected all rows imported and the stale truck removed, got %+v", report)
+	}
+	if _, err := manager.GetTruck("stale"); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected stale truck removed, got %v", err)
+	}
+}
+
+func TestStartImportBadInput(t *testing.T) {
+	manager := NewFleetManager()
+	ops, _ := NewOperations("")
+	if _, err := manager.StartImport(ops, strings.NewReader("{}"), Format("xml"), MergeUpdate); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected unknown format error, got %v", err)
+	}
+	if len(ops.List()) != 0 {
+		t.Errorf("Expected no operation started for unreadable input")
+	}
+}
+
+func TestImportRowsCanceled(t *testing.T) {
+	rows := make([]importRow, 4)
+	for i := range rows {
+		rows[i].truck = Truck{ID: fmt.Sprint(i), Capacity: 1}
+	}
+	manager := NewFleetManager()
+	manager.AddTruck("stale", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	report, err := manager.importRows(ctx, rows, MergeReplace, 2, func(done, total int) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+	if report.Imported != 2 || report.Removed != 0 {
+		t.Errorf("Expected first chunk kept and nothing removed, got %+v", report)
+	}
+	if _, err := manager.GetTruck("stale"); err != nil {
+		t.Errorf("Expected stale truck kept after cancel, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Option configures a truck manager at construction time
+type Option func(*managerOptions)
+
+// managerOptions collects the settings applied by Options
+type managerOptions struct {
+	storage Storage
+	logger  *slog.Logger
+	metrics *Metrics
+	shards  int
+	audit   *AuditLog
+	// capacity is the number of trucks to allocate room for up front
+	capacity  int
+	retention time.Duration
+	// purgeEvery, if set, starts RunPurge with purgeCtx; see WithPurgeEvery
+	purgeCtx   context.Context
+	purgeEvery time.Duration
+}
+
+// WithStorage persists the fleet to s. Every mutation is written to the
+// storage before it is applied in memory.
+func WithStorage(s Storage) Option {
+	return func(o *managerOptions) {
+		o.storage = s
+	}
+}
+
+// WithLogger logs every add, remove, update, load and unload to l, with the
+// truck ID, the capacity and load before and after, the request ID carried
+// by the context and any error
+func WithLogger(l *slog.Logger) Option {
+	return func(o *managerOptions) {
+		o.logger = l
+	}
+}
+
+// WithCapacity allocates room for n trucks up front, so a fleet of known
+// size is built without growing its index. It is a hint, not a limit; see
+// SetLimits for that.
+func WithCapacity(n int) Option {
+	return func(o *managerOptions) {
+		if n > 0 {
+			o.capacity = n
+		}
+	}
+}
+
+// applyOptions folds opts into a managerOptions value
+func applyOptions(opts []Option) managerOptions {
+	o := managerOptions{retention: DefaultRetention}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
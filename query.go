@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// TruckFilter reports whether a truck matches. A nil filter matches every
+// truck. Filters run under the manager's read lock and must not retain or
+// modify the truck they are given.
+type TruckFilter func(t Truck) bool
+
+// matches reports whether t satisfies the filter
+func (f TruckFilter) matches(t *Truck) bool {
+	return f == nil || f(*t)
+}
+
+// Exists reports whether a truck with the given ID is in the fleet
+func (tm *truckManager) Exists(id string) bool {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	_, exist := tm.truck(id)
+	return exist
+}
+
+// Count returns the number of trucks matching filter without copying them out
+func (tm *truckManager) Count(filter TruckFilter) int {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	if filter == nil {
+		return len(tm.trucks)
+	}
+
+	n := 0
+	for _, slot := range tm.trucks {
+		if filter.matches(slot.load()) {
+			n++
+		}
+	}
+	return n
+}
+
+// FindTrucks returns the trucks matching filter, ordered by ID. Only matching
+// trucks are copied, so callers need not list the whole fleet to filter it.
+func (tm *truckManager) FindTrucks(filter TruckFilter) ([]Truck, error) {
+	return tm.FindTrucksContext(context.Background(), filter)
+}
+
+// FindTrucksContext is FindTrucks with cancellation
+func (tm *truckManager) FindTrucksContext(ctx context.Context, filter TruckFilter) ([]Truck, error) {
+	if err := tm.rlockContext(ctx); err != nil {
+		return nil, err
+	}
+	defer tm.RUnlock()
+
+	found := []Truck{}
+	for _, id := range tm.ids {
+		if truck := tm.trucks[id].load(); filter.matches(truck) {
+			found = append(found, truck.clone())
+		}
+	}
+	return found, nil
+}
+
+// TruckStatus describes how full a truck is
+type TruckStatus string
+
+// Truck statuses
+const (
+	StatusEmpty   TruckStatus = "empty"
+	StatusPartial TruckStatus = "partial"
+	StatusFull    TruckStatus = "full"
+)
+
+// Status derives the truck's status from its load and capacity
+func (t Truck) Status() TruckStatus {
+	switch {
+	case t.CurrentLoad == 0:
+		return StatusEmpty
+	case t.CurrentLoad >= t.Capacity:
+		return StatusFull
+	default:
+		return StatusPartial
+	}
+}
+
+// MinCapacity matches trucks with at least the given capacity
+func MinCapacity(capacity int) TruckFilter {
+	return func(t Truck) bool { return t.Capacity >= capacity }
+}
+
+// MaxCapacity matches trucks with at most the given capacity
+func MaxCapacity(capacity int) TruckFilter {
+	return func(t Truck) bool { return t.Capacity <= capacity }
+}
+
+// MinLoad matches trucks carrying at least the given load
+func MinLoad(load int) TruckFilter {
+	return func(t Truck) bool { return t.CurrentLoad >= load }
+}
+
+// MaxLoad matches trucks carrying at most the given load
+func MaxLoad(load int) TruckFilter {
+	return func(t Truck) bool { return t.CurrentLoad <= load }
+}
+
+// IDPrefix matches trucks whose ID starts with prefix
+func IDPrefix(prefix string) TruckFilter {
+	return func(t Truck) bool { return strings.HasPrefix(t.ID, prefix) }
+}
+
+// HasStatus matches trucks in any of the given statuses
+func HasStatus(statuses ...TruckStatus) TruckFilter {
+	return func(t Truck) bool {
+		status := t.Status()
+		for _, s := range statuses {
+			if s == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// And matches trucks that satisfy every filter. With no filters it matches all.
+func And(filters ...TruckFilter) TruckFilter {
+	return func(t Truck) bool {
+		for _, f := range filters {
+			if !f.matches(&t) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches trucks that satisfy at least one filter. With no filters it matches none.
+func Or(filters ...TruckFilter) TruckFilter {
+	return func(t Truck) bool {
+		for _, f := range filters {
+			if f.matches(&t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches trucks that do not satisfy filter
+func Not(filter TruckFilter) TruckFilter {
+	return func(t Truck) bool { return !filter.matches(&t) }
+}
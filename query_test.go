@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExists(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if !manager.Exists("1") {
+		t.Errorf("Expected truck 1 to exist")
+	}
+	if manager.Exists("2") {
+		t.Errorf("Expected truck 2 not to exist")
+	}
+}
+
+func TestCount(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.AddTruck("3", 300)
+
+	if n := manager.Count(nil); n != 3 {
+		t.Errorf("Expected 3 trucks, got %d", n)
+	}
+
+	heavy := func(t Truck) bool { return t.Capacity >= 200 }
+	if n := manager.Count(heavy); n != 2 {
+		t.Errorf("Expected 2 heavy trucks, got %d", n)
+	}
+}
+
+func TestFindTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("east-1", 100)
+	manager.AddTruck("east-2", 500)
+	manager.AddTruck("west-1", 300)
+	manager.LoadCargo("east-2", 500)
+	manager.LoadCargo("west-1", 100)
+
+	tests := []struct {
+		name     string
+		filter   TruckFilter
+		expected []string
+	}{
+		{"nil matches all", nil, []string{"east-1", "east-2", "west-1"}},
+		{"prefix", IDPrefix("east-"), []string{"east-1", "east-2"}},
+		{"capacity range", And(MinCapacity(200), MaxCapacity(400)), []string{"west-1"}},
+		{"load", MinLoad(100), []string{"east-2", "west-1"}},
+		{"status", HasStatus(StatusEmpty, StatusFull), []string{"east-1", "east-2"}},
+		{"or", Or(HasStatus(StatusPartial), MaxLoad(0)), []string{"east-1", "west-1"}},
+		{"not", And(IDPrefix("east-"), Not(HasStatus(StatusFull))), []string{"east-1"}},
+		{"nothing", Or(), []string{}},
+	}
+
+	for _, tt := range tests {
+		trucks, err := manager.FindTrucks(tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", tt.name, err)
+		}
+		ids := make([]string, len(trucks))
+		for i, truck := range trucks {
+			ids[i] = truck.ID
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("%s: Expected %v, got %v", tt.name, tt.expected, ids)
+		}
+	}
+}
+
+func TestTruckStatus(t *testing.T) {
+	tests := []struct {
+		truck    Truck
+		expected TruckStatus
+	}{
+		{Truck{Capacity: 100}, StatusEmpty},
+		{Truck{Capacity: 100, CurrentLoad: 40}, StatusPartial},
+		{Truck{Capacity: 100, CurrentLoad: 100}, StatusFull},
+		{Truck{}, StatusEmpty},
+	}
+	for _, tt := range tests {
+		if got := tt.truck.Status(); got != tt.expected {
+			t.Errorf("Expected %s for %+v, got %s", tt.expected, tt.truck, got)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordedCall is one mutating FleetManager call as written to a recording
+type RecordedCall struct {
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	ID   string    `json:"id"`
+	// Cargo is the call's numeric argument: the capacity for add and
+	// update, the amount moved for load and unload
+	Cargo int    `json:"cargo,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder wraps a FleetManager and writes every mutating call to w as a
+// JSON line. Mutating calls are serialized so the recording order matches
+// the order the calls were applied in.
+type Recorder struct {
+	manager FleetManager
+	w       io.Writer
+	enc     *json.Encoder
+	mu      sync.Mutex
+	err     error
+}
+
+// NewRecorder creates a Recorder that forwards calls to manager and records them to w
+func NewRecorder(manager FleetManager, w io.Writer) *Recorder {
+	return &Recorder{
+		manager: manager,
+		w:       w,
+		enc:     json.NewEncoder(w),
+	}
+}
+
+// AddTruck forwards the call and records it
+func (r *Recorder) AddTruck(id string, capacity int) error {
+	return r.record(OpAdd, id, capacity, func() error {
+		return r.manager.AddTruck(id, capacity)
+	})
+}
+
+// GetTruck forwards the call; reads are not recorded
+func (r *Recorder) GetTruck(id string) (Truck, error) {
+	return r.manager.GetTruck(id)
+}
+
+// ListTrucks forwards the call; reads are not recorded
+func (r *Recorder) ListTrucks(offset, limit int) ([]Truck, error) {
+	return r.manager.ListTrucks(offset, limit)
+}
+
+// RemoveTruck forwards the call and records it
+func (r *Recorder) RemoveTruck(id string) error {
+	return r.record(OpRemove, id, 0, func() error {
+		return r.manager.RemoveTruck(id)
+	})
+}
+
+// UpdateTruckCargo forwards the call and records it
+func (r *Recorder) UpdateTruckCargo(id string, capacity int) error {
+	return r.record(OpUpdate, id, capacity, func() error {
+		return r.manager.UpdateTruckCargo(id, capacity)
+	})
+}
+
+// LoadCargo forwards the call and records it
+func (r *Recorder) LoadCargo(id string, amount int) error {
+	return r.record(OpLoad, id, amount, func() error {
+		return r.manager.LoadCargo(id, amount)
+	})
+}
+
+// UnloadCargo forwards the call and records it
+func (r *Recorder) UnloadCargo(id string, amount int) error {
+	return r.record(OpUnload, id, amount, func() error {
+		return r.manager.UnloadCargo(id, amount)
+	})
+}
+
+// Err returns the first error encountered while writing the recording
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Flush flushes the underlying writer if it buffers, and reports any recording error
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.w.(interface{ Flush() error }); ok && r.err == nil {
+		r.err = f.Flush()
+	}
+	return r.err
+}
+
+// record applies call and appends its outcome to the recording
+func (r *Recorder) record(op, id string, value int, call func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := RecordedCall{Time: time.Now(), Op: op, ID: id, Cargo: value}
+	err := call()
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	// A broken recording must not fail the caller's operation
+	if r.err == nil {
+		r.err = r.enc.Encode(entry)
+	}
+	return err
+}
+
+// ReplayReport summarizes a replay run
+type ReplayReport struct {
+	Calls      int           // calls re-applied
+	Errors     int           // calls that returned an error during replay
+	Mismatches int           // calls whose outcome differed from the recording
+	Elapsed    time.Duration // wall time spent replaying
+}
+
+// Replay re-applies a recording to manager. When paced is true the original
+// gaps between calls are reproduced; otherwise calls are applied back to back.
+func Replay(r io.Reader, manager FleetManager, paced bool) (ReplayReport, error) {
+	var report ReplayReport
+	var prev time.Time
+	start := time.Now()
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var call RecordedCall
+		if err := dec.Decode(&call); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return report, fmt.Errorf("decoding call %d: %w", report.Calls+1, err)
+		}
+
+		if paced && !prev.IsZero() {
+			if gap := call.Time.Sub(prev); gap > 0 {
+				time.Sleep(gap)
+			}
+		}
+		prev = call.Time
+
+		var err error
+		switch call.Op {
+		case OpAdd:
+			err = manager.AddTruck(call.ID, call.Cargo)
+		case OpUpdate:
+			err = manager.UpdateTruckCargo(call.ID, call.Cargo)
+		case OpRemove:
+			err = manager.RemoveTruck(call.ID)
+		case OpLoad:
+			err = manager.LoadCargo(call.ID, call.Cargo)
+		case OpUnload:
+			err = manager.UnloadCargo(call.ID, call.Cargo)
+		default:
+			return report, fmt.Errorf("call %d: unknown operation %q", report.Calls+1, call.Op)
+		}
+
+		report.Calls++
+		outcome := ""
+		if err != nil {
+			report.Errors++
+			outcome = err.Error()
+		}
+		if outcome != call.Error {
+			report.Mismatches++
+		}
+	}
+
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+// runReplayCommand replays a recording against a fresh in-process manager
+func runReplayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	paced := fs.Bool("paced", false, "reproduce the original pacing between calls")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: replay [-paced] <recording>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manager := NewFleetManager()
+	report, err := Replay(f, manager, *paced)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replayed %d calls in %v: %d errors, %d outcome mismatches\n",
+		report.Calls, report.Elapsed.Round(time.Millisecond), report.Errors, report.Mismatches)
+	return nil
+}
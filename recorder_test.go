@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRecordsMutations(t *testing.T) {
+	manager := NewFleetManager()
+	var buf bytes.Buffer
+	recorder := NewRecorder(manager, &buf)
+
+	recorder.AddTruck("1", 100)
+	recorder.GetTruck("1")
+	recorder.UpdateTruckCargo("1", 200)
+	recorder.AddTruck("1", 300)
+	recorder.RemoveTruck("1")
+
+	if err := recorder.Err(); err != nil {
+		t.Fatalf("Expected no recording error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 recorded calls, got %d", len(lines))
+	}
+	if !strings.Contains(lines[2], ErrTruckExist.Error()) {
+		t.Errorf("Expected duplicate add to record its error, got %s", lines[2])
+	}
+}
+
+func TestReplay(t *testing.T) {
+	source := NewFleetManager()
+	var buf bytes.Buffer
+	recorder := NewRecorder(source, &buf)
+	recorder.AddTruck("1", 100)
+	recorder.AddTruck("2", 200)
+	recorder.UpdateTruckCargo("1", 150)
+	recorder.RemoveTruck("2")
+	recorder.RemoveTruck("3")
+
+	target := NewFleetManager()
+	report, err := Replay(&buf, target, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if report.Calls != 5 {
+		t.Errorf("Expected 5 replayed calls, got %d", report.Calls)
+	}
+	if report.Errors != 1 {
+		t.Errorf("Expected 1 replay error, got %d", report.Errors)
+	}
+	if report.Mismatches != 0 {
+		t.Errorf("Expected no mismatches, got %d", report.Mismatches)
+	}
+
+	truck, err := target.GetTruck("1")
+	if err != nil || truck.Capacity != 150 {
+		t.Errorf("Expected truck 1 with capacity 150, got %+v, %v", truck, err)
+	}
+	if _, err := target.GetTruck("2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestReplayDetectsMismatch(t *testing.T) {
+	source := NewFleetManager()
+	var buf bytes.Buffer
+	recorder := NewRecorder(source, &buf)
+	recorder.AddTruck("1", 100)
+
+	// The target already has the truck, so the add now fails
+	target := NewFleetManager()
+	target.AddTruck("1", 100)
+
+	report, err := Replay(&buf, target, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Mismatches != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", report.Mismatches)
+	}
+}
+
+func TestRecorderRecordsCargoMoves(t *testing.T) {
+	source := NewFleetManager()
+	var buf bytes.Buffer
+	recorder := NewRecorder(source, &buf)
+	recorder.AddTruck("1", 100)
+	recorder.LoadCargo("1", 60)
+	recorder.UnloadCargo("1", 20)
+
+	target := NewFleetManager()
+	if _, err := Replay(&buf, target, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := target.GetTruck("1")
+	if truck.CurrentLoad != 40 {
+		t.Errorf("Expected replayed load 40, got %d", truck.CurrentLoad)
+	}
+}
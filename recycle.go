@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrTruckNotRemoved is returned when restoring or purging a truck that is not in the recycle bin
+var ErrTruckNotRemoved = errors.New("truck not in recycle bin")
+
+// DefaultRetention is how long removed trucks stay restorable unless WithRetention says otherwise
+const DefaultRetention = 30 * 24 * time.Hour
+
+// RemovedTruck is a truck in the recycle bin
+type RemovedTruck struct {
+	Truck     Truck     `json:"truck"`
+	RemovedAt time.Time `json:"removed_at"`
+	// PurgeAt is when the truck becomes due for permanent deletion
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// recycledTruck is a recycle bin entry with the records removal dropped.
+// Only the RemovedTruck part is kept in storage.
+type recycledTruck struct {
+	RemovedTruck
+	maintenance []MaintenanceWindow
+	fuel        []FuelRecord
+}
+
+// WithRetention sets how long RemoveTruck keeps removed trucks restorable
+// before a purge deletes them for good. A retention of zero turns the recycle
+// bin off, so removal is permanent. Negative values are ignored.
+func WithRetention(d time.Duration) Option {
+	return func(o *managerOptions) {
+		if d >= 0 {
+			o.retention = d
+		}
+	}
+}
+
+// WithPurgeEvery starts a goroutine running RunPurge with interval once the
+// manager is built, until ctx is done. Only NewFleetManager and
+// OpenTruckManager start it. Non-positive intervals are ignored.
+func WithPurgeEvery(ctx context.Context, interval time.Duration) Option {
+	return func(o *managerOptions) {
+		if interval > 0 {
+			o.purgeCtx, o.purgeEvery = ctx, interval
+		}
+	}
+}
+
+// recycleLocked removes a truck from the fleet and, unless the bin is off,
+// puts it in the recycle bin together with its maintenance windows and fuel
+// records. The bin entry is stored before the truck is deleted from
+// storage, so a restart can't lose both. Callers must hold the write lock.
+func (tm *truckManager) recycleLocked(id string) (Truck, error) {
+	if tm.retention == 0 {
+		return tm.removeLocked(id)
+	}
+	truck, exist := tm.truck(id)
+	if !exist {
+		return Truck{}, ErrTruckNotFound
+	}
+
+	now := time.Now()
+	entry := &recycledTruck{
+		RemovedTruck: RemovedTruck{Truck: truck.clone(), RemovedAt: now, PurgeAt: now.Add(tm.retention)},
+		maintenance:  tm.maintenance[id],
+		fuel:         tm.fuel[id],
+	}
+	// A truck removed once before may already have a bin entry
+	prior := tm.recycled[id]
+	if err := tm.setRecycledLocked(id, entry); err != nil {
+		return Truck{}, err
+	}
+	removed, err := tm.removeLocked(id)
+	if err != nil {
+		tm.setRecycledLocked(id, prior)
+		return Truck{}, err
+	}
+	return removed, nil
+}
+
+// setRecycledLocked makes r the bin entry for id, or deletes the entry if r
+// is nil, in storage first when it keeps the bin and then in memory.
+// Callers must hold the write lock.
+func (tm *truckManager) setRecycledLocked(id string, r *recycledTruck) error {
+	if s, ok := tm.storage.(RecycleBinStorage); ok {
+		var err error
+		if r != nil {
+			err = s.SaveRemoved(r.RemovedTruck)
+		} else if err = s.DeleteRemoved(id); errors.Is(err, ErrTruckNotRemoved) {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if r != nil {
+		tm.recycled[id] = r
+	} else {
+		delete(tm.recycled, id)
+	}
+	return nil
+}
+
+// ListRemovedTrucks returns the trucks in the recycle bin ordered by ID
+func (tm *truckManager) ListRemovedTrucks() []RemovedTruck {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	removed := make([]RemovedTruck, 0, len(tm.recycled))
+	for _, r := range tm.recycled {
+		entry := r.RemovedTruck
+		entry.Truck = r.Truck.clone()
+		removed = append(removed, entry)
+	}
+	slices.SortFunc(removed, func(a, b RemovedTruck) int { return strings.Compare(a.Truck.ID, b.Truck.ID) })
+	return removed
+}
+
+// RestoreTruck puts a removed truck back into the fleet with its
+// maintenance windows and fuel records. Its driver and yard slot are taken
+// back if they are still free. It fails with ErrTruckExist if a truck with
+// the same ID has been added since.
+func (tm *truckManager) RestoreTruck(id string) error {
+	return tm.RestoreTruckContext(context.Background(), id)
+}
+
+// RestoreTruckContext is RestoreTruck with cancellation
+func (tm *truckManager) RestoreTruckContext(ctx context.Context, id string) (err error) {
+	var after *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(ctx, "restore", id, start, nil, after, err) }()
+
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	if err := tm.lockContext(ctx); err != nil {
+		return err
+	}
+	defer tm.Unlock()
+
+	r, exist := tm.recycled[id]
+	if !exist {
+		return ErrTruckNotRemoved
+	}
+	truck := r.Truck.clone()
+	truck.Version++
+	if err := tm.setRecycledLocked(id, nil); err != nil {
+		return err
+	}
+	if err := tm.addLocked(truck); err != nil {
+		tm.setRecycledLocked(id, r)
+		return fmt.Errorf("restoring %s: %w", id, err)
+	}
+	if r.maintenance != nil {
+		tm.maintenance[id] = r.maintenance
+	}
+	if r.fuel != nil {
+		tm.fuel[id] = r.fuel
+	}
+	after = tm.snapshotLocked(id)
+	tm.auditLocked(ctx, "restore", id, nil, after)
+	return nil
+}
+
+// PurgeTruck permanently deletes a truck from the recycle bin ahead of its retention
+func (tm *truckManager) PurgeTruck(id string) (err error) {
+	start := time.Now()
+	defer func() { tm.recordMutation(context.Background(), "purge", id, start, nil, nil, err) }()
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	r, exist := tm.recycled[id]
+	if !exist {
+		return ErrTruckNotRemoved
+	}
+	return tm.purgeLocked(r)
+}
+
+// PurgeRemoved permanently deletes the trucks whose retention ended by now
+// and returns how many were deleted
+func (tm *truckManager) PurgeRemoved(now time.Time) int {
+	tm.Lock()
+	defer tm.Unlock()
+
+	var result BatchResult
+	for _, r := range tm.recycled {
+		if r.PurgeAt.After(now) {
+			continue
+		}
+		// A failed purge is retried on the next pass
+		if err := tm.purgeLocked(r); err != nil {
+			result.Failed = append(result.Failed, BatchItemError{ID: r.Truck.ID, Err: err})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, r.Truck.ID)
+	}
+	if len(result.Succeeded) > 0 || len(result.Failed) > 0 {
+		tm.recordBatch("purge", result)
+	}
+	return len(result.Succeeded)
+}
+
+// purgeLocked deletes a recycle bin entry. Callers must hold the write lock.
+func (tm *truckManager) purgeLocked(r *recycledTruck) error {
+	if err := tm.setRecycledLocked(r.Truck.ID, nil); err != nil {
+		return err
+	}
+	tm.auditLocked(context.Background(), "purge", r.Truck.ID, &r.Truck, nil)
+	return nil
+}
+
+// startPurge runs RunPurge in the background if WithPurgeEvery was given
+func (tm *truckManager) startPurge(o managerOptions) {
+	if o.purgeEvery > 0 {
+		go tm.RunPurge(o.purgeCtx, o.purgeEvery)
+	}
+}
+
+// RunPurge purges expired trucks every interval until ctx is done. Run it
+// in its own goroutine.
+func (tm *truckManager) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			tm.PurgeRemoved(now)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestoreTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Ada")
+	manager.AssignDriver("1", "d1")
+	manager.SetFuelTank("1", 400)
+	manager.RecordRefuel("1", 100, 150)
+
+	if err := manager.RemoveTruck("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := manager.GetTruck("1"); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected removed truck to be gone, got %v", err)
+	}
+	removed := manager.ListRemovedTrucks()
+	if len(removed) != 1 || removed[0].Truck.ID != "1" {
+		t.Fatalf("Expected truck 1 in the recycle bin, got %+v", removed)
+	}
+	if !removed[0].PurgeAt.Equal(removed[0].RemovedAt.Add(DefaultRetention)) {
+		t.Errorf("Expected purge after the default retention, got %v", removed[0].PurgeAt)
+	}
+
+	if err := manager.RestoreTruck("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, err := manager.GetTruck("1")
+	if err != nil || truck.Capacity != 100 || truck.DriverID != "d1" {
+		t.Errorf("Expected truck restored with its driver, got %+v, %v", truck, err)
+	}
+	if records, _ := manager.FuelRecords("1", time.Time{}, time.Now()); len(records) != 1 {
+		t.Errorf("Expected fuel records restored, got %d", len(records))
+	}
+	if len(manager.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected empty recycle bin after restore")
+	}
+	if err := manager.RestoreTruck("1"); !errors.Is(err, ErrTruckNotRemoved) {
+		t.Errorf("Expected not removed error, got %v", err)
+	}
+}
+
+func TestRestoreTruckIDTaken(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+	manager.AddTruck("1", 200)
+
+	if err := manager.RestoreTruck("1"); !errors.Is(err, ErrTruckExist) {
+		t.Errorf("Expected truck exists error, got %v", err)
+	}
+	if len(manager.ListRemovedTrucks()) != 1 {
+		t.Errorf("Expected the removed truck to stay in the recycle bin")
+	}
+}
+
+func TestRetentionOff(t *testing.T) {
+	manager := NewFleetManager(WithRetention(0))
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+
+	if len(manager.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected no recycle bin with zero retention")
+	}
+}
+
+func TestPurgeRemoved(t *testing.T) {
+	manager := NewFleetManager(WithRetention(time.Hour))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.RemoveTruck("1")
+	manager.RemoveTruck("2")
+
+	if purged := manager.PurgeRemoved(time.Now()); purged != 0 {
+		t.Errorf("Expected nothing purged before retention ends, got %d", purged)
+	}
+	if purged := manager.PurgeRemoved(time.Now().Add(time.Hour)); purged != 2 {
+		t.Errorf("Expected 2 trucks purged, got %d", purged)
+	}
+	if err := manager.RestoreTruck("1"); !errors.Is(err, ErrTruckNotRemoved) {
+		t.Errorf("Expected purged truck to be unrecoverable, got %v", err)
+	}
+}
+
+func TestPurgeTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+
+	if err := manager.PurgeTruck("1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := manager.PurgeTruck("1"); !errors.Is(err, ErrTruckNotRemoved) {
+		t.Errorf("Expected not removed error, got %v", err)
+	}
+}
+
+func TestRunPurge(t *testing.T) {
+	manager := NewFleetManager(WithRetention(time.Nanosecond))
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		manager.RunPurge(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(manager.ListRemovedTrucks()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+	if len(manager.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected background purge to empty the recycle bin")
+	}
+}
+
+func TestRemoveTrucksRollbackRecycleBin(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	_, err := manager.RemoveTrucks([]string{"1", "missing"}, AllOrNothing())
+	if err == nil {
+		t.Fatalf("Expected the batch to fail")
+	}
+	if len(manager.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected rolled-back removal to leave the recycle bin empty")
+	}
+	if _, err := manager.GetTruck("1"); err != nil {
+		t.Errorf("Expected truck 1 back in the fleet, got %v", err)
+	}
+}
+
+func TestImportReplaceRecyclesTrucks(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+
+	report, err := manager.ImportFleet(strings.NewReader("id,capacity,current_load\n1,100,0\n"), FormatCSV, MergeReplace)
+	if err != nil || report.Removed != 1 {
+		t.Fatalf("Expected 1 truck removed, got %+v, %v", report, err)
+	}
+	if err := manager.RestoreTruck("2"); err != nil {
+		t.Errorf("Expected truck removed by the import to be restorable, got %v", err)
+	}
+}
+
+func TestRecycleBinSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.json")
+	storage, _ := NewJSONFileStorage(path)
+	manager, _ := OpenTruckManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.RemoveTruck("1")
+	manager.RemoveTruck("2")
+	manager.PurgeTruck("2")
+
+	storage, _ = NewJSONFileStorage(path)
+	restarted, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	removed := restarted.ListRemovedTrucks()
+	if len(removed) != 1 || removed[0].Truck.ID != "1" {
+		t.Fatalf("Expected truck 1 in the recycle bin after restart, got %+v", removed)
+	}
+	if err := restarted.RestoreTruck("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	storage, _ = NewJSONFileStorage(path)
+	again, _ := OpenTruckManager(WithStorage(storage))
+	if truck, err := again.GetTruck("1"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected restored truck 1 stored, got %+v, %v", truck, err)
+	}
+	if len(again.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected restore to empty the stored recycle bin")
+	}
+}
+
+func TestWithPurgeEvery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager := NewFleetManager(WithRetention(time.Nanosecond), WithPurgeEvery(ctx, time.Millisecond))
+	manager.AddTruck("1", 100)
+	manager.RemoveTruck("1")
+
+	deadline := time.Now().Add(time.Second)
+	for len(manager.ListRemovedTrucks()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(manager.ListRemovedTrucks()) != 0 {
+		t.Errorf("Expected the purge loop started by the option to empty the recycle bin")
+	}
+}
+
+func TestOpenTruckManagerPurgesLoadedBin(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.SaveRemoved(RemovedTruck{Truck: Truck{ID: "1", Capacity: 100}, PurgeAt: time.Now().Add(-time.Hour)})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager, err := OpenTruckManager(WithStorage(storage), WithPurgeEvery(ctx, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(manager.ListRemovedTrucks()) != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if removed, _ := storage.ListRemoved(); len(removed) != 0 {
+		t.Errorf("Expected the purge loop to delete the loaded bin entry, got %+v", removed)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Errors for truck reservations
+var (
+	ErrTruckReserved       = errors.New("truck already reserved")
+	ErrReservationNotFound = errors.New("reservation not found or expired")
+	ErrInvalidReservation  = errors.New("reservation duration must be positive")
+)
+
+// ReservationToken identifies one reservation. Only its holder can release it.
+type ReservationToken string
+
+// Reservation holds a truck for a job until Expires
+type Reservation struct {
+	Token   ReservationToken
+	TruckID string
+	Expires time.Time
+}
+
+// active reports whether the reservation still holds at t
+func (r Reservation) active(t time.Time) bool {
+	return t.Before(r.Expires)
+}
+
+// ReserveTruck holds a truck for duration. While the reservation holds,
+// selection skips the truck, CheckAvailable rejects it with
+// ErrTruckUnavailable and further reservations fail with ErrTruckReserved.
+// The reservation ends by itself once the duration passes, or earlier with
+// ReleaseReservation. A truck with maintenance planned during the
+// reservation cannot be reserved.
+func (tm *truckManager) ReserveTruck(id string, duration time.Duration) (ReservationToken, error) {
+	if id == "" {
+		return "", ErrEmptyID
+	}
+	if duration <= 0 {
+		return "", ErrInvalidReservation
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.trucks[id]; !exist {
+		return "", ErrTruckNotFound
+	}
+	now := time.Now()
+	if r, exist := tm.reservations[id]; exist && r.active(now) {
+		return "", fmt.Errorf("%w: %s is reserved until %s", ErrTruckReserved, id, r.Expires.Format(time.RFC3339))
+	}
+	expires := now.Add(duration)
+	for _, w := range tm.maintenance[id] {
+		if w.Start.Before(expires) && w.End.After(now) {
+			return "", fmt.Errorf("%w: %s is in maintenance from %s", ErrTruckUnavailable, id, w.Start.Format(time.RFC3339))
+		}
+	}
+
+	token := ReservationToken(rand.Text())
+	tm.reservations[id] = Reservation{Token: token, TruckID: id, Expires: expires}
+	return token, nil
+}
+
+// ReleaseReservation ends a reservation before it expires
+func (tm *truckManager) ReleaseReservation(token ReservationToken) error {
+	tm.Lock()
+	defer tm.Unlock()
+
+	now := time.Now()
+	for id, r := range tm.reservations {
+		if r.Token == token && r.active(now) {
+			delete(tm.reservations, id)
+			return nil
+		}
+	}
+	return ErrReservationNotFound
+}
+
+// GetReservation returns the reservation holding a truck, if any
+func (tm *truckManager) GetReservation(truckID string) (Reservation, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	r, exist := tm.reservations[truckID]
+	if !exist || !r.active(time.Now()) {
+		return Reservation{}, ErrReservationNotFound
+	}
+	return r, nil
+}
+
+// ListReservations returns the reservations that still hold, ordered by truck ID
+func (tm *truckManager) ListReservations() []Reservation {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	now := time.Now()
+	var reservations []Reservation
+	for _, r := range tm.reservations {
+		if r.active(now) {
+			reservations = append(reservations, r)
+		}
+	}
+	slices.SortFunc(reservations, func(a, b Reservation) int { return strings.Compare(a.TruckID, b.TruckID) })
+	return reservations
+}
+
+// checkReservedLocked returns an error wrapping ErrTruckUnavailable if the
+// truck is reserved at the given time. Callers must hold the lock.
+func (tm *truckManager) checkReservedLocked(truckID string, at time.Time) error {
+	if r, exist := tm.reservations[truckID]; exist && r.active(at) {
+		return fmt.Errorf("%w: %s is reserved until %s", ErrTruckUnavailable, truckID, r.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReserveTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	token, err := manager.ReserveTruck("1", time.Hour)
+	if err != nil || token == "" {
+		t.Fatalf("Expected a token, got %q, %v", token, err)
+	}
+	if _, err := manager.ReserveTruck("1", time.Hour); !errors.Is(err, ErrTruckReserved) {
+		t.Errorf("Expected truck reserved error, got %v", err)
+	}
+	if err := manager.CheckAvailable("1", time.Now()); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected reserved truck unavailable, got %v", err)
+	}
+	if r, err := manager.GetReservation("1"); err != nil || r.Token != token {
+		t.Errorf("Expected reservation %s, got %+v, %v", token, r, err)
+	}
+	if list := manager.ListReservations(); len(list) != 1 || list[0].TruckID != "1" {
+		t.Errorf("Expected 1 reservation, got %+v", list)
+	}
+
+	// The reservation is over by the time it expires
+	if err := manager.CheckAvailable("1", time.Now().Add(2*time.Hour)); err != nil {
+		t.Errorf("Expected truck available after expiry, got %v", err)
+	}
+
+	if err := manager.ReleaseReservation(token); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.CheckAvailable("1", time.Now()); err != nil {
+		t.Errorf("Expected released truck available, got %v", err)
+	}
+	if err := manager.ReleaseReservation(token); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected reservation not found error, got %v", err)
+	}
+}
+
+func TestReserveTruckExpires(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	token, _ := manager.ReserveTruck("1", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := manager.GetReservation("1"); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected expired reservation gone, got %v", err)
+	}
+	if err := manager.ReleaseReservation(token); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected expired reservation not releasable, got %v", err)
+	}
+	if _, err := manager.ReserveTruck("1", time.Hour); err != nil {
+		t.Errorf("Expected truck reservable again, got %v", err)
+	}
+}
+
+func TestReserveTruckInvalid(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if _, err := manager.ReserveTruck("", time.Hour); !errors.Is(err, ErrEmptyID) {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+	if _, err := manager.ReserveTruck("1", 0); !errors.Is(err, ErrInvalidReservation) {
+		t.Errorf("Expected invalid reservation error, got %v", err)
+	}
+	if _, err := manager.ReserveTruck("2", time.Hour); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestReserveTruckMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	now := time.Now()
+	manager.ScheduleMaintenance("1", now.Add(30*time.Minute), now.Add(2*time.Hour), "brakes")
+
+	if _, err := manager.ReserveTruck("1", time.Hour); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected maintenance during the reservation to block it, got %v", err)
+	}
+	if _, err := manager.ReserveTruck("1", 10*time.Minute); err != nil {
+		t.Errorf("Expected reservation ending before maintenance, got %v", err)
+	}
+}
+
+func TestSelectTruckSkipsReserved(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.ReserveTruck("1", time.Hour)
+
+	for range 4 {
+		truck, err := manager.SelectTruck(SelectRoundRobin, nil)
+		if err != nil || truck.ID != "2" {
+			t.Errorf("Expected truck 2, got %+v, %v", truck, err)
+		}
+	}
+
+	availability, _ := manager.Availability("1", time.Now())
+	if availability.Available || availability.Reservation == nil {
+		t.Errorf("Expected reservation reported by availability, got %+v", availability)
+	}
+}
+
+func TestRemoveTruckDropsReservation(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.ReserveTruck("1", time.Hour)
+	manager.RemoveTruck("1")
+	manager.AddTruck("1", 100)
+
+	if _, err := manager.ReserveTruck("1", time.Hour); err != nil {
+		t.Errorf("Expected re-added truck unreserved, got %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Errors for route management
+var (
+	ErrRouteNotFound    = errors.New("route not found")
+	ErrRouteExist       = errors.New("route already exist")
+	ErrInvalidRoute     = errors.New("invalid route")
+	ErrRouteWeightLimit = errors.New("load exceeds route weight limit")
+	ErrNoRouteAssigned  = errors.New("truck has no route assigned")
+)
+
+// RouteManager manages the routes trucks can be assigned to
+type RouteManager interface {
+	CreateRoute(route Route) error
+	GetRoute(id string) (Route, error)
+	RemoveRoute(id string) error
+	ListRoutes() []Route
+}
+
+// Route is a planned trip from Origin to Destination through Waypoints in
+// order. Several trucks may run the same route.
+type Route struct {
+	ID                string        `json:"id"`
+	Origin            string        `json:"origin"`
+	Destination       string        `json:"destination"`
+	Waypoints         []string      `json:"waypoints,omitempty"`
+	DistanceKm        float64       `json:"distance_km"`
+	EstimatedDuration time.Duration `json:"estimated_duration"`
+	// WeightLimit is the heaviest load allowed on the route; 0 means no limit
+	WeightLimit int `json:"weight_limit"`
+}
+
+// validate checks a route's fields
+func (r Route) validate() error {
+	if r.ID == "" {
+		return ErrEmptyID
+	}
+	if r.Origin == "" || r.Destination == "" {
+		return fmt.Errorf("%w: origin and destination are required", ErrInvalidRoute)
+	}
+	if r.DistanceKm < 0 || r.EstimatedDuration < 0 || r.WeightLimit < 0 {
+		return fmt.Errorf("%w: distance, duration and weight limit must not be negative", ErrInvalidRoute)
+	}
+	return nil
+}
+
+// clone returns an independent copy of the route
+func (r *Route) clone() Route {
+	c := *r
+	c.Waypoints = append([]string(nil), r.Waypoints...)
+	return c
+}
+
+// allows reports whether a truck carrying load may run the route
+func (r *Route) allows(load int) bool {
+	return r.WeightLimit == 0 || load <= r.WeightLimit
+}
+
+// CreateRoute registers a new route. Routes are held in memory only; the
+// configured Storage does not persist them.
+func (tm *truckManager) CreateRoute(route Route) error {
+	if err := route.validate(); err != nil {
+		return err
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.routes[route.ID]; exist {
+		return ErrRouteExist
+	}
+	stored := route.clone()
+	tm.routes[route.ID] = &stored
+	return nil
+}
+
+// GetRoute retrieves a route by ID
+func (tm *truckManager) GetRoute(id string) (Route, error) {
+	if id == "" {
+		return Route{}, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	route, exist := tm.routes[id]
+	if !exist {
+		return Route{}, ErrRouteNotFound
+	}
+	return route.clone(), nil
+}
+
+// RemoveRoute removes a route, first releasing every truck assigned to it
+func (tm *truckManager) RemoveRoute(id string) error {
+	if id == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.routes[id]; !exist {
+		return ErrRouteNotFound
+	}
+	for _, truckID := range tm.ids {
+		if tm.trucks[truckID].load().RouteID == id {
+			if err := tm.setRouteLocked(truckID, ""); err != nil {
+				return err
+			}
+		}
+	}
+	delete(tm.routes, id)
+	return nil
+}
+
+// ListRoutes returns every route ordered by ID
+func (tm *truckManager) ListRoutes() []Route {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	routes := make([]Route, 0, len(tm.routes))
+	for _, route := range tm.routes {
+		routes = append(routes, route.clone())
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].ID < routes[j].ID })
+	return routes
+}
+
+// AssignRoute puts a truck on a route, replacing any route it already had.
+// It is rejected with ErrRouteWeightLimit if the truck's current load is
+// over the route's weight limit, and with ErrTruckUnavailable if the truck
+// is under maintenance. While assigned, cargo changes that would take the
+// truck over the limit are rejected too. The truck's driver, if any, gets a
+// MessageRouteChanged message.
+func (tm *truckManager) AssignRoute(truckID, routeID string) error {
+	if truckID == "" || routeID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	route, exist := tm.routes[routeID]
+	if !exist {
+		return ErrRouteNotFound
+	}
+	if truck.RouteID == routeID {
+		return nil
+	}
+	if !route.allows(truck.CurrentLoad) {
+		return fmt.Errorf("%w: %s carries %d, route %s allows %d", ErrRouteWeightLimit, truckID, truck.CurrentLoad, routeID, route.WeightLimit)
+	}
+	if err := tm.checkAvailableLocked(truckID, time.Now()); err != nil {
+		return err
+	}
+	return tm.setRouteLocked(truckID, routeID)
+}
+
+// UnassignRoute takes a truck off its route
+func (tm *truckManager) UnassignRoute(truckID string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if truck.RouteID == "" {
+		return ErrNoRouteAssigned
+	}
+	return tm.setRouteLocked(truckID, "")
+}
+
+// setRouteLocked changes the route of an existing truck. Callers must hold the write lock.
+func (tm *truckManager) setRouteLocked(truckID, routeID string) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.Version++
+	updated.RouteID = routeID
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+
+	old := *truck
+	tm.trucks[truckID].store(&updated)
+	tm.emit(RouteChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("route", truckID, &old, &updated)
+	if _, exist := tm.drivers[updated.DriverID]; exist {
+		tm.sendLocked(updated.DriverID, MessageRouteChanged, routeChangeNotice(truckID, routeID))
+	}
+	return nil
+}
+
+// routeChangeNotice is the message a driver gets when their truck's route changes
+func routeChangeNotice(truckID, routeID string) string {
+	if routeID == "" {
+		return fmt.Sprintf("Truck %s has been taken off its route", truckID)
+	}
+	return fmt.Sprintf("Truck %s is now assigned to route %s", truckID, routeID)
+}
+
+// checkRouteLocked returns an error if t is over the weight limit of its
+// assigned route. Callers must hold the lock.
+func (tm *truckManager) checkRouteLocked(t Truck) error {
+	route, exist := tm.routes[t.RouteID]
+	if !exist || route.allows(t.CurrentLoad) {
+		return nil
+	}
+	return fmt.Errorf("%w: route %s allows %d", ErrRouteWeightLimit, t.RouteID, route.WeightLimit)
+}
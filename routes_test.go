@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateRoute(t *testing.T) {
+	manager := NewFleetManager()
+	route := Route{ID: "r1", Origin: "Nairobi", Destination: "Mombasa", Waypoints: []string{"Voi"}, DistanceKm: 480, EstimatedDuration: 8 * time.Hour, WeightLimit: 500}
+
+	if err := manager.CreateRoute(route); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.CreateRoute(route); err != ErrRouteExist {
+		t.Errorf("Expected route exist error, got %v", err)
+	}
+	if err := manager.CreateRoute(Route{ID: "r2", Origin: "Nairobi"}); !errors.Is(err, ErrInvalidRoute) {
+		t.Errorf("Expected invalid route error, got %v", err)
+	}
+
+	got, err := manager.GetRoute("r1")
+	if err != nil || got.Destination != "Mombasa" || len(got.Waypoints) != 1 {
+		t.Errorf("Expected route r1 to Mombasa via Voi, got %+v, %v", got, err)
+	}
+
+	// Routes handed out are copies
+	got.Waypoints[0] = "Mtito Andei"
+	again, _ := manager.GetRoute("r1")
+	if again.Waypoints[0] != "Voi" {
+		t.Errorf("Expected stored waypoints to be unchanged, got %v", again.Waypoints)
+	}
+}
+
+func TestAssignRoute(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+	manager.CreateRoute(Route{ID: "r1", Origin: "A", Destination: "B", WeightLimit: 500})
+
+	if err := manager.AssignRoute("1", "r1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.RouteID != "r1" {
+		t.Errorf("Expected route r1, got %q", truck.RouteID)
+	}
+
+	if err := manager.AssignRoute("2", "r1"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+	if err := manager.AssignRoute("1", "r2"); err != ErrRouteNotFound {
+		t.Errorf("Expected route not found error, got %v", err)
+	}
+}
+
+func TestAssignRouteWeightLimit(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+	manager.LoadCargo("1", 600)
+	manager.CreateRoute(Route{ID: "r1", Origin: "A", Destination: "B", WeightLimit: 500})
+
+	if err := manager.AssignRoute("1", "r1"); !errors.Is(err, ErrRouteWeightLimit) {
+		t.Errorf("Expected route weight limit error, got %v", err)
+	}
+
+	manager.UnloadCargo("1", 200)
+	if err := manager.AssignRoute("1", "r1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.LoadCargo("1", 200); !errors.Is(err, ErrRouteWeightLimit) {
+		t.Errorf("Expected loading past the route limit to fail, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.CurrentLoad != 400 {
+		t.Errorf("Expected load 400, got %d", truck.CurrentLoad)
+	}
+}
+
+func TestAssignRouteUnderMaintenance(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+	manager.CreateRoute(Route{ID: "r1", Origin: "A", Destination: "B"})
+	now := time.Now()
+	manager.ScheduleMaintenance("1", now.Add(-time.Hour), now.Add(time.Hour), "tyres")
+
+	if err := manager.AssignRoute("1", "r1"); !errors.Is(err, ErrTruckUnavailable) {
+		t.Errorf("Expected truck unavailable error, got %v", err)
+	}
+}
+
+func TestUnassignAndRemoveRoute(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+	manager.AddTruck("2", 1000)
+	manager.CreateRoute(Route{ID: "r1", Origin: "A", Destination: "B"})
+	manager.AssignRoute("1", "r1")
+	manager.AssignRoute("2", "r1")
+
+	if err := manager.UnassignRoute("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.UnassignRoute("1"); err != ErrNoRouteAssigned {
+		t.Errorf("Expected no route assigned error, got %v", err)
+	}
+
+	if err := manager.RemoveRoute("r1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("2")
+	if truck.RouteID != "" {
+		t.Errorf("Expected truck 2 to be released from the removed route, got %q", truck.RouteID)
+	}
+	if len(manager.ListRoutes()) != 0 {
+		t.Errorf("Expected no routes left")
+	}
+}
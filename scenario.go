@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+)
+
+// Scenario is a declarative description of a fleet used to seed a manager
+// for demos, tests and benchmarks
+type Scenario struct {
+	Seed     int64           `json:"seed"`     // seed for generated values, so runs are reproducible
+	Trucks   []ScenarioTruck `json:"trucks"`   // trucks added exactly as listed
+	Generate []TruckGroup    `json:"generate"` // groups of generated trucks
+}
+
+// ScenarioTruck is a single truck listed in a scenario
+type ScenarioTruck struct {
+	ID          string `json:"id"`
+	Capacity    int    `json:"capacity"`
+	CurrentLoad int    `json:"current_load"`
+}
+
+// TruckGroup generates Count empty trucks named Prefix-0, Prefix-1, ... with
+// capacity drawn uniformly from [MinCapacity, MaxCapacity]
+type TruckGroup struct {
+	Prefix      string `json:"prefix"`
+	Count       int    `json:"count"`
+	MinCapacity int    `json:"min_capacity"`
+	MaxCapacity int    `json:"max_capacity"`
+}
+
+// LoadScenario decodes a scenario from JSON, rejecting unknown fields
+func LoadScenario(r io.Reader) (Scenario, error) {
+	var s Scenario
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return Scenario{}, fmt.Errorf("decoding scenario: %w", err)
+	}
+	return s, nil
+}
+
+// LoadScenarioFile reads a scenario from the named file
+func LoadScenarioFile(path string) (Scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	defer f.Close()
+	return LoadScenario(f)
+}
+
+// SeedFleet adds every truck described by the scenario to the manager and
+// returns how many were added. It stops at the first failed add.
+func SeedFleet(manager FleetManager, s Scenario) (int, error) {
+	added := 0
+	for _, t := range s.Trucks {
+		if err := manager.AddTruck(t.ID, t.Capacity); err != nil {
+			return added, fmt.Errorf("adding truck %q: %w", t.ID, err)
+		}
+		if t.CurrentLoad > 0 {
+			if err := manager.LoadCargo(t.ID, t.CurrentLoad); err != nil {
+				return added, fmt.Errorf("loading truck %q: %w", t.ID, err)
+			}
+		}
+		added++
+	}
+
+	rng := rand.New(rand.NewSource(s.Seed))
+	for _, g := range s.Generate {
+		if g.Prefix == "" || g.Count < 0 || g.MinCapacity < 0 || g.MaxCapacity < g.MinCapacity {
+			return added, fmt.Errorf("invalid truck group %+v", g)
+		}
+		for i := 0; i < g.Count; i++ {
+			id := fmt.Sprintf("%s-%d", g.Prefix, i)
+			capacity := g.MinCapacity + rng.Intn(g.MaxCapacity-g.MinCapacity+1)
+			if err := manager.AddTruck(id, capacity); err != nil {
+				return added, fmt.Errorf("adding truck %q: %w", id, err)
+			}
+			added++
+		}
+	}
+	return added, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testScenario = `{
+	"seed": 42,
+	"trucks": [{"id": "depot-1", "capacity": 500}],
+	"generate": [{"prefix": "reefer", "count": 10, "min_capacity": 100, "max_capacity": 200}]
+}`
+
+func TestSeedFleet(t *testing.T) {
+	scenario, err := LoadScenario(strings.NewReader(testScenario))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	manager := NewFleetManager()
+	added, err := SeedFleet(manager, scenario)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if added != 11 || len(manager.trucks) != 11 {
+		t.Errorf("Expected 11 trucks, got %d added and %d stored", added, len(manager.trucks))
+	}
+
+	for _, slot := range manager.trucks {
+		if truck := slot.load(); truck.ID != "depot-1" && (truck.Capacity < 100 || truck.Capacity > 200) {
+			t.Errorf("Expected generated capacity within [100, 200], got %d", truck.Capacity)
+		}
+	}
+}
+
+func TestSeedFleetDeterministic(t *testing.T) {
+	scenario, _ := LoadScenario(strings.NewReader(testScenario))
+
+	first := NewFleetManager()
+	second := NewFleetManager()
+	SeedFleet(first, scenario)
+	SeedFleet(second, scenario)
+
+	for id, slot := range first.trucks {
+		if truck := slot.load(); second.trucks[id].load().Capacity != truck.Capacity {
+			t.Errorf("Expected same capacity for %s, got %d and %d", id, truck.Capacity, second.trucks[id].load().Capacity)
+		}
+	}
+}
+
+func TestSeedFleetDuplicate(t *testing.T) {
+	scenario := Scenario{Trucks: []ScenarioTruck{{ID: "1", Capacity: 1}, {ID: "1", Capacity: 2}}}
+
+	manager := NewFleetManager()
+	added, err := SeedFleet(manager, scenario)
+	if !errors.Is(err, ErrTruckExist) {
+		t.Errorf("Expected truck exists error, got %v", err)
+	}
+	if added != 1 {
+		t.Errorf("Expected 1 truck added before the failure, got %d", added)
+	}
+}
+
+func TestLoadScenarioUnknownField(t *testing.T) {
+	if _, err := LoadScenario(strings.NewReader(`{"drivers": []}`)); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// ErrNoTruckAvailable is returned when no truck matches a selection
+var ErrNoTruckAvailable = errors.New("no truck available")
+
+// SelectionStrategy decides which of the matching trucks SelectTruck returns
+type SelectionStrategy int
+
+const (
+	// SelectRandom picks uniformly among matching trucks
+	SelectRandom SelectionStrategy = iota
+	// SelectRoundRobin cycles through matching trucks in ID order
+	SelectRoundRobin
+	// SelectLeastLoaded picks the truck with the least cargo on board, ties broken by ID
+	SelectLeastLoaded
+	// SelectMostRecentlyIdle picks the empty truck that became idle last
+	SelectMostRecentlyIdle
+)
+
+// SelectTruck picks one available truck matching filter according to
+// strategy, for simple dispatch cases that don't need a full optimizer
+func (tm *truckManager) SelectTruck(strategy SelectionStrategy, filter TruckFilter) (Truck, error) {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	// Trucks under maintenance or reserved are never handed out
+	now := time.Now()
+	var candidates []*Truck
+	for _, id := range tm.ids {
+		truck := tm.trucks[id].load()
+		if tm.checkAvailableLocked(id, now) == nil && tm.checkReservedLocked(id, now) == nil && filter.matches(truck) {
+			candidates = append(candidates, truck)
+		}
+	}
+
+	var chosen *Truck
+	switch strategy {
+	case SelectRandom:
+		if len(candidates) > 0 {
+			chosen = candidates[rand.IntN(len(candidates))]
+		}
+	case SelectRoundRobin:
+		chosen = tm.nextRoundRobin(candidates)
+	case SelectLeastLoaded:
+		for _, truck := range candidates {
+			if chosen == nil || truck.CurrentLoad < chosen.CurrentLoad {
+				chosen = truck
+			}
+		}
+	case SelectMostRecentlyIdle:
+		var latest time.Time
+		for _, truck := range candidates {
+			since, idle := tm.trucks[truck.ID].idleSince()
+			if idle && (chosen == nil || since.After(latest)) {
+				chosen, latest = truck, since
+			}
+		}
+	}
+
+	if chosen == nil {
+		return Truck{}, ErrNoTruckAvailable
+	}
+	return chosen.clone(), nil
+}
+
+// nextRoundRobin returns the first candidate after the previously selected
+// ID, wrapping around. Candidates must be in ID order.
+func (tm *truckManager) nextRoundRobin(candidates []*Truck) *Truck {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	tm.rrMu.Lock()
+	defer tm.rrMu.Unlock()
+
+	i := sort.Search(len(candidates), func(i int) bool { return candidates[i].ID > tm.rrLast })
+	if i == len(candidates) {
+		i = 0
+	}
+	tm.rrLast = candidates[i].ID
+	return candidates[i]
+}
+
+// trackIdle records when a truck becomes empty and forgets it once it is
+// loaded again. Callers must hold the write lock or the truck's shard lock.
+func (tm *truckManager) trackIdle(id string, load int) {
+	slot := tm.trucks[id]
+	if load != 0 {
+		slot.idle.Store(0)
+		return
+	}
+	slot.idle.CompareAndSwap(0, time.Now().UnixNano())
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectTruckRandom(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+
+	truck, err := manager.SelectTruck(SelectRandom, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if truck.ID != "1" && truck.ID != "2" {
+		t.Errorf("Expected one of the fleet's trucks, got %s", truck.ID)
+	}
+}
+
+func TestSelectTruckRoundRobin(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("a", 1)
+	manager.AddTruck("b", 1)
+	manager.AddTruck("c", 1)
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		truck, _ := manager.SelectTruck(SelectRoundRobin, nil)
+		got = append(got, truck.ID)
+	}
+	if got[0] != "a" || got[1] != "b" || got[2] != "c" || got[3] != "a" {
+		t.Errorf("Expected a, b, c, a, got %v", got)
+	}
+
+	// Removing the next truck skips to the one after it
+	manager.RemoveTruck("b")
+	truck, _ := manager.SelectTruck(SelectRoundRobin, nil)
+	if truck.ID != "c" {
+		t.Errorf("Expected c, got %s", truck.ID)
+	}
+}
+
+func TestSelectTruckLeastLoaded(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 1000)
+	manager.AddTruck("2", 1000)
+	manager.AddTruck("3", 1000)
+	manager.LoadCargo("1", 300)
+	manager.LoadCargo("2", 100)
+	manager.LoadCargo("3", 200)
+
+	truck, _ := manager.SelectTruck(SelectLeastLoaded, nil)
+	if truck.ID != "2" {
+		t.Errorf("Expected truck 2, got %s", truck.ID)
+	}
+
+	// The filter narrows the candidates before the strategy applies
+	notTwo := func(t Truck) bool { return t.ID != "2" }
+	truck, _ = manager.SelectTruck(SelectLeastLoaded, notTwo)
+	if truck.ID != "3" {
+		t.Errorf("Expected truck 3, got %s", truck.ID)
+	}
+}
+
+func TestSelectTruckMostRecentlyIdle(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	time.Sleep(time.Millisecond)
+	manager.AddTruck("2", 100)
+	manager.LoadCargo("2", 50)
+	manager.UnloadCargo("2", 50)
+	manager.AddTruck("3", 100)
+	manager.LoadCargo("3", 50)
+
+	truck, err := manager.SelectTruck(SelectMostRecentlyIdle, nil)
+	if err != nil || truck.ID != "2" {
+		t.Errorf("Expected truck 2, got %+v, %v", truck, err)
+	}
+
+	manager.LoadCargo("2", 50)
+	truck, _ = manager.SelectTruck(SelectMostRecentlyIdle, nil)
+	if truck.ID != "1" {
+		t.Errorf("Expected truck 1 once truck 2 carries cargo, got %s", truck.ID)
+	}
+}
+
+func TestSelectTruckNoneAvailable(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("1", 10)
+
+	if _, err := manager.SelectTruck(SelectMostRecentlyIdle, nil); err != ErrNoTruckAvailable {
+		t.Errorf("Expected no truck available error, got %v", err)
+	}
+	none := func(t Truck) bool { return false }
+	if _, err := manager.SelectTruck(SelectRandom, none); err != ErrNoTruckAvailable {
+		t.Errorf("Expected no truck available error, got %v", err)
+	}
+}
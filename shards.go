@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultShards is the number of truck lock shards when WithShards isn't given
+const defaultShards = 64
+
+// truckSlot holds one truck of the fleet. The Truck it points to is never
+// modified; a change stores a new one. Readers holding the manager's read
+// lock therefore always see a whole truck, even while a writer holding the
+// truck's shard lock replaces it.
+type truckSlot struct {
+	cur atomic.Pointer[Truck]
+	// idle is when the truck last became empty, in Unix nanoseconds; 0 while it carries cargo
+	idle atomic.Int64
+}
+
+// newTruckSlot returns a slot holding t
+func newTruckSlot(t *Truck) *truckSlot {
+	s := &truckSlot{}
+	s.cur.Store(t)
+	return s
+}
+
+// load returns the current truck. It must not be modified.
+func (s *truckSlot) load() *Truck {
+	return s.cur.Load()
+}
+
+// store replaces the current truck
+func (s *truckSlot) store(t *Truck) {
+	s.cur.Store(t)
+}
+
+// idleSince returns when the truck became empty, if it is empty
+func (s *truckSlot) idleSince() (time.Time, bool) {
+	ns := s.idle.Load()
+	if ns == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, ns), true
+}
+
+// WithShards sets the number of lock shards trucks are spread across.
+// Updates to trucks in different shards run in parallel; a single shard
+// serializes every update, as one mutex would. Values below 1 are ignored.
+func WithShards(n int) Option {
+	return func(o *managerOptions) {
+		o.shards = n
+	}
+}
+
+// newShards returns n shard locks, or defaultShards if n is below 1
+func newShards(n int) []sync.Mutex {
+	if n < 1 {
+		n = defaultShards
+	}
+	return make([]sync.Mutex, n)
+}
+
+// truck returns the current state of the truck with the given ID. Callers
+// must hold the read or write lock.
+func (tm *truckManager) truck(id string) (*Truck, bool) {
+	slot, exist := tm.trucks[id]
+	if !exist {
+		return nil, false
+	}
+	return slot.load(), true
+}
+
+// shard returns the lock guarding updates to the truck with the given ID
+func (tm *truckManager) shard(id string) *sync.Mutex {
+	// FNV-1a, inlined to keep the hot path free of allocations
+	h := uint32(2166136261)
+	for i := 0; i < len(id); i++ {
+		h ^= uint32(id[i])
+		h *= 16777619
+	}
+	return &tm.shards[h%uint32(len(tm.shards))]
+}
+
+// lockTruckContext takes what an update to one existing truck needs: the
+// manager's read lock, which keeps the set of trucks fixed, and the truck's
+// shard lock. Updates to trucks in other shards proceed in parallel. The
+// returned function releases both.
+func (tm *truckManager) lockTruckContext(ctx context.Context, id string) (func(), error) {
+	if err := tm.rlockContext(ctx); err != nil {
+		return nil, err
+	}
+	shard := tm.shard(id)
+	shard.Lock()
+	// The deadline may have passed while waiting for the shard
+	if err := ctx.Err(); err != nil {
+		shard.Unlock()
+		tm.RUnlock()
+		return nil, err
+	}
+	return func() {
+		shard.Unlock()
+		tm.RUnlock()
+	}, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShardedConcurrentCargo(t *testing.T) {
+	manager := NewFleetManager(WithShards(4))
+	const numTrucks = 20
+	for i := 0; i < numTrucks; i++ {
+		manager.AddTruck(fmt.Sprintf("truck-%d", i), 100000)
+	}
+
+	const numGoroutines = 64
+	const iterations = 200
+	var wg sync.WaitGroup
+	for g := 0; g < numGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				id := fmt.Sprintf("truck-%d", (g+j)%numTrucks)
+				if err := manager.LoadCargo(id, 1); err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+			}
+		}(g)
+	}
+	// Add, remove and list other trucks while the loads run
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < iterations; j++ {
+			id := fmt.Sprintf("extra-%d", j)
+			manager.AddTruck(id, 10)
+			manager.ListTrucks(0, numTrucks)
+			manager.RemoveTruck(id)
+		}
+	}()
+	wg.Wait()
+
+	total := 0
+	for i := 0; i < numTrucks; i++ {
+		truck, _ := manager.GetTruck(fmt.Sprintf("truck-%d", i))
+		total += truck.CurrentLoad
+	}
+	if total != numGoroutines*iterations {
+		t.Errorf("Expected total load %d, got %d", numGoroutines*iterations, total)
+	}
+}
+
+func TestShardedCapacityLimit(t *testing.T) {
+	manager := NewFleetManager()
+	const numTrucks = 50
+	for i := 0; i < numTrucks; i++ {
+		manager.AddTruck(fmt.Sprintf("truck-%d", i), 10)
+	}
+	manager.SetLimits(FleetLimits{MaxTotalCapacity: numTrucks*10 + 100})
+
+	// Every truck asks for 10 more; only 10 of them fit
+	var wg sync.WaitGroup
+	var granted atomic.Int32
+	for i := 0; i < numTrucks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := manager.UpdateTruckCargo(fmt.Sprintf("truck-%d", i), 20)
+			if err == nil {
+				granted.Add(1)
+			} else if !errors.Is(err, ErrFleetLimitReached) {
+				t.Errorf("Expected ErrFleetLimitReached, got %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if granted.Load() != 10 {
+		t.Errorf("Expected 10 increases to fit, got %d", granted.Load())
+	}
+	if got := manager.totalCapacity.Load(); got != numTrucks*10+100 {
+		t.Errorf("Expected total capacity %d, got %d", numTrucks*10+100, got)
+	}
+}
+
+func TestShardLockDeadline(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	// Hold the shard so the update has to wait past its deadline
+	shard := manager.shard("1")
+	shard.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- manager.UpdateTruckCargoContext(ctx, "1", 200) }()
+	<-ctx.Done()
+	shard.Unlock()
+
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if truck, _ := manager.GetTruck("1"); truck.Capacity != 100 {
+		t.Errorf("Expected capacity unchanged, got %d", truck.Capacity)
+	}
+}
+
+func TestWithShardsIgnoresInvalid(t *testing.T) {
+	manager := NewFleetManager(WithShards(0))
+	if len(manager.shards) != defaultShards {
+		t.Errorf("Expected %d shards, got %d", defaultShards, len(manager.shards))
+	}
+	manager = NewFleetManager(WithShards(1))
+	if len(manager.shards) != 1 {
+		t.Errorf("Expected 1 shard, got %d", len(manager.shards))
+	}
+}
+
+func TestEmitWithoutSubscribers(t *testing.T) {
+	manager := NewFleetManager()
+	events := make(chan FleetEvent, 1)
+	manager.Subscribe(events)
+	manager.Unsubscribe(events)
+
+	manager.AddTruck("1", 100)
+	if manager.events.active() {
+		t.Errorf("Expected no active subscribers")
+	}
+	select {
+	case ev := <-events:
+		t.Errorf("Expected no event after unsubscribing, got %v", ev)
+	default:
+	}
+}
+
+// benchmarkParallelCargo loads and unloads cargo across many trucks from at
+// least 32 goroutines
+func benchmarkParallelCargo(b *testing.B, shards int) {
+	manager := NewFleetManager(WithShards(shards))
+	const numTrucks = 1024
+	ids := make([]string, numTrucks)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("truck-%d", i)
+		manager.AddTruck(ids[i], 1<<30)
+	}
+
+	var next atomic.Uint64
+	b.SetParallelism(32)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			n := next.Add(1)
+			id := ids[n%numTrucks]
+			if n%2 == 0 {
+				manager.LoadCargo(id, 1)
+			} else {
+				manager.UpdateTruckCargo(id, 1<<30)
+			}
+		}
+	})
+}
+
+func BenchmarkCargoSingleLock(b *testing.B) {
+	benchmarkParallelCargo(b, 1)
+}
+
+func BenchmarkCargoSharded(b *testing.B) {
+	benchmarkParallelCargo(b, defaultShards)
+}
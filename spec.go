@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// UpdateSpec describes a change to an existing truck. Only the fields that
+// were set are applied. Specs are plain values: each setter returns a
+// modified copy, so a spec can be shared and extended without aliasing.
+type UpdateSpec struct {
+	capacity    *int
+	currentLoad *int
+}
+
+// NewUpdateSpec returns an empty spec that changes nothing
+func NewUpdateSpec() UpdateSpec {
+	return UpdateSpec{}
+}
+
+// WithCapacity returns a copy of the spec that sets the cargo capacity
+func (s UpdateSpec) WithCapacity(capacity int) UpdateSpec {
+	s.capacity = &capacity
+	return s
+}
+
+// WithCurrentLoad returns a copy of the spec that sets the cargo on board
+func (s UpdateSpec) WithCurrentLoad(load int) UpdateSpec {
+	s.currentLoad = &load
+	return s
+}
+
+// IsEmpty reports whether the spec changes nothing
+func (s UpdateSpec) IsEmpty() bool {
+	return s.capacity == nil && s.currentLoad == nil
+}
+
+// validate checks the fields that were set. Whether the result fits the
+// truck is checked once the spec is applied.
+func (s UpdateSpec) validate() error {
+	if s.capacity != nil && *s.capacity < 0 {
+		return ErrInvalidCargo
+	}
+	if s.currentLoad != nil && *s.currentLoad < 0 {
+		return ErrInvalidCargo
+	}
+	return nil
+}
+
+// apply writes the fields that were set onto t
+func (s UpdateSpec) apply(t *Truck) {
+	if s.capacity != nil {
+		t.Capacity = *s.capacity
+	}
+	if s.currentLoad != nil {
+		t.CurrentLoad = *s.currentLoad
+	}
+}
+
+// clone returns an independent copy of the truck. Callers outside the
+// manager only ever see clones; any reference-typed field added to Truck
+// must be deep-copied here.
+func (t *Truck) clone() Truck {
+	c := *t
+	c.Custom = maps.Clone(t.Custom)
+	c.Tags = maps.Clone(t.Tags)
+	if t.Location != nil {
+		loc := *t.Location
+		c.Location = &loc
+	}
+	return c
+}
+
+// UpdateTruck applies spec to the truck with the given ID
+func (tm *truckManager) UpdateTruck(id string, spec UpdateSpec) error {
+	return tm.UpdateTruckContext(context.Background(), id, spec)
+}
+
+// UpdateTruckContext is UpdateTruck with cancellation
+func (tm *truckManager) UpdateTruckContext(ctx context.Context, id string, spec UpdateSpec) error {
+	return tm.updateTruck(ctx, id, spec, anyVersion)
+}
+
+// UpdateTruckCAS applies spec only if the truck is still at expectedVersion,
+// the Version the caller last read. Otherwise it fails with
+// ErrVersionConflict and changes nothing, so the caller can re-read and retry.
+func (tm *truckManager) UpdateTruckCAS(id string, spec UpdateSpec, expectedVersion uint64) error {
+	if expectedVersion == anyVersion {
+		return fmt.Errorf("%w: expected version %d never exists", ErrVersionConflict, expectedVersion)
+	}
+	return tm.updateTruck(context.Background(), id, spec, expectedVersion)
+}
+
+// UpdateTruckCargoCAS sets the cargo capacity of a truck only if it is still
+// at expectedVersion. See UpdateTruckCAS.
+func (tm *truckManager) UpdateTruckCargoCAS(id string, capacity int, expectedVersion uint64) error {
+	return tm.UpdateTruckCAS(id, NewUpdateSpec().WithCapacity(capacity), expectedVersion)
+}
+
+// anyVersion disables the version check in updateTruck. Trucks start at
+// version 1, so 0 is never a real version.
+const anyVersion uint64 = 0
+
+// updateTruck applies spec after checking the truck is at expectedVersion,
+// unless that is anyVersion
+func (tm *truckManager) updateTruck(ctx context.Context, id string, spec UpdateSpec, expectedVersion uint64) (err error) {
+	var before, after *Truck
+	start := time.Now()
+	defer func() { tm.recordMutation(ctx, "update", id, start, before, after, err) }()
+
+	if id == "" {
+		return ErrEmptyID
+	}
+	if err := spec.validate(); err != nil {
+		return err
+	}
+
+	unlock, err := tm.lockTruckContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if expectedVersion != anyVersion {
+		truck, exist := tm.truck(id)
+		if !exist {
+			return ErrTruckNotFound
+		}
+		if truck.Version != expectedVersion {
+			return fmt.Errorf("%w: %s is at version %d, expected %d", ErrVersionConflict, id, truck.Version, expectedVersion)
+		}
+	}
+
+	before = tm.snapshotLocked(id)
+	if err := tm.updateLocked(id, spec); err != nil {
+		return err
+	}
+	after = tm.snapshotLocked(id)
+	tm.auditLocked(ctx, "update", id, before, after)
+	return nil
+}
+
+// updateLocked applies a validated spec to an existing truck. Callers must
+// hold the write lock, or the read lock and the truck's shard lock.
+func (tm *truckManager) updateLocked(id string, spec UpdateSpec) error {
+	// Check if truck exists
+	truck, exist := tm.truck(id)
+	if !exist {
+		return ErrTruckNotFound
+	}
+
+	updated := truck.clone()
+	spec.apply(&updated)
+	if !spec.IsEmpty() {
+		updated.Version++
+	}
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	if err := tm.checkRouteLocked(updated); err != nil {
+		return err
+	}
+	delta := updated.Capacity - truck.Capacity
+	if err := tm.reserveCapacity(delta); err != nil {
+		return err
+	}
+	if err := tm.persist(updated); err != nil {
+		tm.totalCapacity.Add(-int64(delta))
+		return err
+	}
+
+	old := *truck
+	tm.trucks[id].store(&updated)
+	tm.trackIdle(id, updated.CurrentLoad)
+	if !spec.IsEmpty() {
+		tm.emit(CargoUpdated, id, &old, &updated)
+	}
+	return nil
+}
+
+// UpsertResult reports what UpsertTruck did
+type UpsertResult int
+
+const (
+	// UpsertCreated means the truck did not exist and was added
+	UpsertCreated UpsertResult = iota + 1
+	// UpsertUpdated means an existing truck had the spec applied
+	UpsertUpdated
+)
+
+// String returns the result name
+func (r UpsertResult) String() string {
+	switch r {
+	case UpsertCreated:
+		return "created"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unknown"
+	}
+}
+
+// UpsertTruck applies spec to the truck with the given ID, creating the truck
+// first if it does not exist. Fields the spec leaves unset take their zero
+// value on creation. The check and the write happen under one lock, so
+// concurrent sync jobs cannot race between lookup and add.
+func (tm *truckManager) UpsertTruck(id string, spec UpdateSpec) (result UpsertResult, err error) {
+	var before, after *Truck
+	op := "upsert"
+	start := time.Now()
+	defer func() { tm.recordMutation(context.Background(), op, id, start, before, after, err) }()
+
+	if id == "" {
+		return 0, ErrEmptyID
+	}
+	if err := spec.validate(); err != nil {
+		return 0, err
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.trucks[id]; exist {
+		op = "update"
+		before = tm.snapshotLocked(id)
+		if err := tm.updateLocked(id, spec); err != nil {
+			return 0, err
+		}
+		after = tm.snapshotLocked(id)
+		tm.auditLocked(context.Background(), "update", id, before, after)
+		return UpsertUpdated, nil
+	}
+
+	op = "add"
+	truck := Truck{ID: id}
+	spec.apply(&truck)
+	if err := tm.addLocked(truck); err != nil {
+		return 0, err
+	}
+	after = tm.snapshotLocked(id)
+	tm.auditLocked(context.Background(), "add", id, nil, after)
+	return UpsertCreated, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateTruck(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.UpdateTruck("1", NewUpdateSpec().WithCapacity(300)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 300 {
+		t.Errorf("Expected truck capacity to be 300, got %d", truck.Capacity)
+	}
+}
+
+func TestUpdateTruckEmptySpec(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.UpdateTruck("1", NewUpdateSpec()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 100 {
+		t.Errorf("Expected truck capacity to stay 100, got %d", truck.Capacity)
+	}
+}
+
+func TestUpdateTruckErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.UpdateTruck("", NewUpdateSpec()); err != ErrEmptyID {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+	if err := manager.UpdateTruck("1", NewUpdateSpec().WithCapacity(-1)); err != ErrInvalidCargo {
+		t.Errorf("Expected invalid cargo error, got %v", err)
+	}
+	if err := manager.UpdateTruck("2", NewUpdateSpec().WithCapacity(1)); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestUpdateSpecIsValue(t *testing.T) {
+	base := NewUpdateSpec()
+	withCapacity := base.WithCapacity(10)
+
+	if !base.IsEmpty() {
+		t.Errorf("Expected setter to leave the original spec unchanged")
+	}
+	if withCapacity.IsEmpty() {
+		t.Errorf("Expected spec with cargo to be non-empty")
+	}
+}
+
+func TestGetTruckReturnsCopy(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	truck, _ := manager.GetTruck("1")
+	truck.Capacity = 999
+
+	stored, _ := manager.GetTruck("1")
+	if stored.Capacity != 100 {
+		t.Errorf("Expected stored capacity to stay 100, got %d", stored.Capacity)
+	}
+}
+
+func TestUpsertTruck(t *testing.T) {
+	manager := NewFleetManager()
+
+	result, err := manager.UpsertTruck("1", NewUpdateSpec().WithCapacity(100))
+	if err != nil || result != UpsertCreated {
+		t.Fatalf("Expected created, got %v, %v", result, err)
+	}
+
+	result, err = manager.UpsertTruck("1", NewUpdateSpec().WithCapacity(250))
+	if err != nil || result != UpsertUpdated {
+		t.Fatalf("Expected updated, got %v, %v", result, err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 250 {
+		t.Errorf("Expected truck capacity to be 250, got %d", truck.Capacity)
+	}
+	if len(manager.ids) != 1 {
+		t.Errorf("Expected 1 truck, got %d", len(manager.ids))
+	}
+}
+
+func TestUpsertTruckErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.SetLimits(FleetLimits{MaxTrucks: 1})
+	manager.AddTruck("1", 100)
+
+	if _, err := manager.UpsertTruck("", NewUpdateSpec()); err != ErrEmptyID {
+		t.Errorf("Expected empty ID error, got %v", err)
+	}
+	if _, err := manager.UpsertTruck("1", NewUpdateSpec().WithCapacity(-5)); err != ErrInvalidCargo {
+		t.Errorf("Expected invalid cargo error, got %v", err)
+	}
+	if _, err := manager.UpsertTruck("2", NewUpdateSpec()); !errors.Is(err, ErrFleetLimitReached) {
+		t.Errorf("Expected fleet limit error, got %v", err)
+	}
+}
+
+func TestConcurrentUpsert(t *testing.T) {
+	manager := NewFleetManager()
+	const numGoroutines = 50
+
+	created := make(chan UpsertResult, numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			result, _ := manager.UpsertTruck("1", NewUpdateSpec().WithCapacity(1))
+			created <- result
+		}()
+	}
+
+	creates := 0
+	for i := 0; i < numGoroutines; i++ {
+		if <-created == UpsertCreated {
+			creates++
+		}
+	}
+	if creates != 1 {
+		t.Errorf("Expected exactly 1 create, got %d", creates)
+	}
+}
+
+func TestTruckVersion(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Version != 1 {
+		t.Errorf("Expected a new truck at version 1, got %d", truck.Version)
+	}
+
+	manager.LoadCargo("1", 10)
+	manager.UpdateTruckCargo("1", 200)
+	manager.UpdateTruck("1", NewUpdateSpec())
+	truck, _ = manager.GetTruck("1")
+	if truck.Version != 3 {
+		t.Errorf("Expected version 3 after two changes and an empty update, got %d", truck.Version)
+	}
+}
+
+func TestUpdateTruckCargoCAS(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	read, _ := manager.GetTruck("1")
+
+	if err := manager.UpdateTruckCargoCAS("1", 200, read.Version); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A second writer holding the same read loses
+	if err := manager.UpdateTruckCargoCAS("1", 300, read.Version); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected version conflict error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 200 || truck.Version != 2 {
+		t.Errorf("Expected capacity 200 at version 2, got %+v", truck)
+	}
+
+	if err := manager.UpdateTruckCargoCAS("1", 300, 0); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected version 0 to conflict, got %v", err)
+	}
+	if err := manager.UpdateTruckCargoCAS("2", 300, 1); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestAssignmentsBumpVersion(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddDriver("d1", "Amina")
+	read, _ := manager.GetTruck("1")
+
+	manager.AssignDriver("1", "d1")
+	if err := manager.UpdateTruckCargoCAS("1", 200, read.Version); !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected a driver assignment to count as a modification, got %v", err)
+	}
+}
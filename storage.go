@@ -0,0 +1,398 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Storage persists trucks so a fleet survives restarts. Implementations must
+// be safe for concurrent use and return ErrTruckNotFound from Load and Delete
+// for unknown IDs. Save can run concurrently for different IDs, since the
+// manager persists single-truck updates under that truck's shard lock only;
+// calls for the same ID never overlap.
+type Storage interface {
+	Save(t Truck) error
+	Load(id string) (Truck, error)
+	Delete(id string) error
+	List() ([]Truck, error)
+}
+
+// RecycleBinStorage is implemented by storages that also keep the recycle
+// bin, so removed trucks stay restorable across restarts. DeleteRemoved
+// returns ErrTruckNotRemoved for unknown IDs.
+type RecycleBinStorage interface {
+	SaveRemoved(r RemovedTruck) error
+	DeleteRemoved(id string) error
+	ListRemoved() ([]RemovedTruck, error)
+}
+
+// MemoryStorage is a Storage backed by a map. It does not survive restarts
+// and is mainly useful in tests.
+type MemoryStorage struct {
+	trucks  map[string]Truck
+	removed map[string]RemovedTruck
+	sync.RWMutex
+}
+
+// NewMemoryStorage creates an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{trucks: make(map[string]Truck), removed: make(map[string]RemovedTruck)}
+}
+
+// Save stores or replaces the truck
+func (s *MemoryStorage) Save(t Truck) error {
+	s.Lock()
+	defer s.Unlock()
+	s.trucks[t.ID] = t
+	return nil
+}
+
+// Load returns the stored truck with the given ID
+func (s *MemoryStorage) Load(id string) (Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	t, exist := s.trucks[id]
+	if !exist {
+		return Truck{}, ErrTruckNotFound
+	}
+	return t, nil
+}
+
+// Delete removes the stored truck with the given ID
+func (s *MemoryStorage) Delete(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exist := s.trucks[id]; !exist {
+		return ErrTruckNotFound
+	}
+	delete(s.trucks, id)
+	return nil
+}
+
+// List returns all stored trucks ordered by ID
+func (s *MemoryStorage) List() ([]Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedTrucks(s.trucks), nil
+}
+
+// SaveRemoved stores or replaces a recycle bin entry
+func (s *MemoryStorage) SaveRemoved(r RemovedTruck) error {
+	s.Lock()
+	defer s.Unlock()
+	s.removed[r.Truck.ID] = r
+	return nil
+}
+
+// DeleteRemoved removes the recycle bin entry of the truck with the given ID
+func (s *MemoryStorage) DeleteRemoved(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exist := s.removed[id]; !exist {
+		return ErrTruckNotRemoved
+	}
+	delete(s.removed, id)
+	return nil
+}
+
+// ListRemoved returns the recycle bin entries ordered by truck ID
+func (s *MemoryStorage) ListRemoved() ([]RemovedTruck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedRemoved(s.removed), nil
+}
+
+// JSONFileStorage is a Storage that keeps the whole fleet in a single JSON
+// file. Every change rewrites the file through a temporary file and rename,
+// so a crash leaves either the old or the new contents on disk.
+type JSONFileStorage struct {
+	path    string
+	trucks  map[string]Truck
+	removed map[string]RemovedTruck
+	sync.RWMutex
+}
+
+// jsonFleetFile is the on-disk layout of a JSONFileStorage
+type jsonFleetFile struct {
+	Trucks  []Truck        `json:"trucks"`
+	Removed []RemovedTruck `json:"removed,omitempty"`
+}
+
+// NewJSONFileStorage opens the fleet file at path, creating it on first save
+func NewJSONFileStorage(path string) (*JSONFileStorage, error) {
+	s := &JSONFileStorage{path: path, trucks: make(map[string]Truck), removed: make(map[string]RemovedTruck)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file jsonFleetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading fleet file %s: %w", path, err)
+	}
+	for _, t := range file.Trucks {
+		s.trucks[t.ID] = t
+	}
+	for _, r := range file.Removed {
+		s.removed[r.Truck.ID] = r
+	}
+	return s, nil
+}
+
+// Save stores or replaces the truck and rewrites the file
+func (s *JSONFileStorage) Save(t Truck) error {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, existed := s.trucks[t.ID]
+	s.trucks[t.ID] = t
+	if err := s.flush(); err != nil {
+		// Keep memory in step with what is on disk
+		if existed {
+			s.trucks[t.ID] = prev
+		} else {
+			delete(s.trucks, t.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Load returns the stored truck with the given ID
+func (s *JSONFileStorage) Load(id string) (Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	t, exist := s.trucks[id]
+	if !exist {
+		return Truck{}, ErrTruckNotFound
+	}
+	return t, nil
+}
+
+// Delete removes the truck and rewrites the file
+func (s *JSONFileStorage) Delete(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, exist := s.trucks[id]
+	if !exist {
+		return ErrTruckNotFound
+	}
+	delete(s.trucks, id)
+	if err := s.flush(); err != nil {
+		s.trucks[id] = prev
+		return err
+	}
+	return nil
+}
+
+// List returns all stored trucks ordered by ID
+func (s *JSONFileStorage) List() ([]Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedTrucks(s.trucks), nil
+}
+
+// SaveRemoved stores or replaces a recycle bin entry and rewrites the file
+func (s *JSONFileStorage) SaveRemoved(r RemovedTruck) error {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, existed := s.removed[r.Truck.ID]
+	s.removed[r.Truck.ID] = r
+	if err := s.flush(); err != nil {
+		if existed {
+			s.removed[r.Truck.ID] = prev
+		} else {
+			delete(s.removed, r.Truck.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteRemoved removes a recycle bin entry and rewrites the file
+func (s *JSONFileStorage) DeleteRemoved(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, exist := s.removed[id]
+	if !exist {
+		return ErrTruckNotRemoved
+	}
+	delete(s.removed, id)
+	if err := s.flush(); err != nil {
+		s.removed[id] = prev
+		return err
+	}
+	return nil
+}
+
+// ListRemoved returns the recycle bin entries ordered by truck ID
+func (s *JSONFileStorage) ListRemoved() ([]RemovedTruck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedRemoved(s.removed), nil
+}
+
+// flush atomically replaces the file with the current contents. Callers must hold the write lock.
+func (s *JSONFileStorage) flush() error {
+	data, err := json.MarshalIndent(jsonFleetFile{Trucks: sortedTrucks(s.trucks), Removed: sortedRemoved(s.removed)}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic replaces path with data through a synced temporary file
+// and rename, so readers see either the old or the new contents
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sortedTrucks returns the map values ordered by ID
+func sortedTrucks(trucks map[string]Truck) []Truck {
+	list := make([]Truck, 0, len(trucks))
+	for _, t := range trucks {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// sortedRemoved returns the recycle bin entries ordered by truck ID
+func sortedRemoved(removed map[string]RemovedTruck) []RemovedTruck {
+	list := make([]RemovedTruck, 0, len(removed))
+	for _, r := range removed {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Truck.ID < list[j].Truck.ID })
+	return list
+}
+
+// OpenTruckManager creates a manager and loads any trucks already held in
+// its storage. Use it instead of NewFleetManager when WithStorage is given.
+// The WithPurgeEvery loop starts only once the stored recycle bin is loaded.
+func OpenTruckManager(opts ...Option) (*truckManager, error) {
+	o := applyOptions(opts)
+	tm := newTruckManager(o)
+	if err := tm.Load(); err != nil {
+		return nil, err
+	}
+	tm.startPurge(o)
+	return &tm, nil
+}
+
+// Load replaces the in-memory fleet with the contents of the storage
+func (tm *truckManager) Load() error {
+	if tm.storage == nil {
+		return nil
+	}
+
+	stored, err := tm.storage.List()
+	if err != nil {
+		return fmt.Errorf("loading fleet: %w", err)
+	}
+	bin, keepsBin := tm.storage.(RecycleBinStorage)
+	var removed []RemovedTruck
+	if keepsBin {
+		if removed, err = bin.ListRemoved(); err != nil {
+			return fmt.Errorf("loading recycle bin: %w", err)
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if keepsBin {
+		// Maintenance windows and fuel records are not stored, so restored trucks come back without them
+		tm.recycled = make(map[string]*recycledTruck, len(removed))
+		for _, r := range removed {
+			tm.recycled[r.Truck.ID] = &recycledTruck{RemovedTruck: r}
+		}
+	}
+
+	tm.trucks = make(map[string]*truckSlot, len(stored))
+	tm.ids = tm.ids[:0]
+	tm.totalCapacity.Store(0)
+	tm.tags = make(tagIndex)
+	tm.geo.reset()
+	for _, driver := range tm.drivers {
+		driver.TruckID = ""
+	}
+	for _, y := range tm.yards {
+		y.slots = make(map[int]string)
+		y.present = make(map[string]int)
+	}
+	for _, t := range stored {
+		truck := t
+		// Files written before trucks were versioned have no version
+		if truck.Version == 0 {
+			truck.Version = 1
+		}
+		if _, exist := tm.routes[truck.RouteID]; !exist {
+			truck.RouteID = ""
+		}
+		tm.claimDriverLocked(&truck)
+		tm.claimYardLocked(&truck)
+		tm.trucks[t.ID] = newTruckSlot(&truck)
+		tm.insertID(t.ID)
+		tm.totalCapacity.Add(int64(t.Capacity))
+		tm.trackIdle(t.ID, t.CurrentLoad)
+		tm.tags.add(t.ID, truck.Tags)
+		tm.geo.move(t.ID, truck.Location)
+	}
+	return nil
+}
+
+// persist writes t to the storage, if any. Callers must hold the write lock,
+// or the read lock plus the truck's shard lock.
+func (tm *truckManager) persist(t Truck) error {
+	if tm.storage == nil {
+		return nil
+	}
+	return tm.storage.Save(t)
+}
+
+// unpersist deletes id from the storage, if any. Callers must hold the write
+// lock, or the read lock plus the truck's shard lock.
+func (tm *truckManager) unpersist(id string) error {
+	if tm.storage == nil {
+		return nil
+	}
+	if err := tm.storage.Delete(id); err != nil && !errors.Is(err, ErrTruckNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONFileStorageSurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.json")
+
+	storage, err := NewJSONFileStorage(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	manager, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.UpdateTruckCargo("1", 150)
+	manager.RemoveTruck("2")
+
+	// Reopen from disk as a restarted process would
+	storage, err = NewJSONFileStorage(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	restarted, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	truck, err := restarted.GetTruck("1")
+	if err != nil || truck.Capacity != 150 {
+		t.Errorf("Expected truck 1 with capacity 150, got %+v, %v", truck, err)
+	}
+	if _, err := restarted.GetTruck("2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+func TestJSONFileStorageMissingFile(t *testing.T) {
+	storage, err := NewJSONFileStorage(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	trucks, _ := storage.List()
+	if len(trucks) != 0 {
+		t.Errorf("Expected empty storage, got %v", trucks)
+	}
+}
+
+func TestJSONFileStorageCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fleet.json")
+	os.WriteFile(path, []byte("{not json"), 0o644)
+
+	if _, err := NewJSONFileStorage(path); err == nil {
+		t.Errorf("Expected error for corrupt fleet file")
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.Save(Truck{ID: "b", Capacity: 2})
+	storage.Save(Truck{ID: "a", Capacity: 1})
+
+	truck, err := storage.Load("a")
+	if err != nil || truck.Capacity != 1 {
+		t.Errorf("Expected truck a with capacity 1, got %+v, %v", truck, err)
+	}
+
+	trucks, _ := storage.List()
+	if len(trucks) != 2 || trucks[0].ID != "a" {
+		t.Errorf("Expected trucks ordered by ID, got %v", trucks)
+	}
+
+	if err := storage.Delete("c"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}
+
+// failingStorage rejects every write
+type failingStorage struct {
+	*MemoryStorage
+}
+
+var errStorageDown = errors.New("storage down")
+
+func (failingStorage) Save(Truck) error    { return errStorageDown }
+func (failingStorage) Delete(string) error { return errStorageDown }
+
+func TestStorageFailureLeavesFleetUnchanged(t *testing.T) {
+	manager := NewFleetManager(WithStorage(failingStorage{NewMemoryStorage()}))
+
+	if err := manager.AddTruck("1", 100); err != errStorageDown {
+		t.Errorf("Expected storage error, got %v", err)
+	}
+	if _, err := manager.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not to be added, got %v", err)
+	}
+}
+
+func TestUpdateStorageFailure(t *testing.T) {
+	storage := NewMemoryStorage()
+	manager := NewFleetManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+
+	manager.storage = failingStorage{storage}
+	if err := manager.UpdateTruckCargo("1", 200); err != errStorageDown {
+		t.Errorf("Expected storage error, got %v", err)
+	}
+	if err := manager.RemoveTruck("1"); err != errStorageDown {
+		t.Errorf("Expected storage error, got %v", err)
+	}
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Capacity != 100 {
+		t.Errorf("Expected truck capacity to stay 100, got %d", truck.Capacity)
+	}
+}
+
+func TestLoadDropsUnknownAssignments(t *testing.T) {
+	storage := NewMemoryStorage()
+	storage.Save(Truck{ID: "1", Capacity: 100, DriverID: "d1", RouteID: "r1"})
+
+	manager, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.DriverID != "" || truck.RouteID != "" {
+		t.Errorf("Expected drivers and routes unknown to this manager to be dropped, got %+v", truck)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"maps"
+	"sort"
+)
+
+// ErrInvalidTag is returned for a tag with an empty key
+var ErrInvalidTag = errors.New("invalid tag")
+
+// tagIndex maps each tag key to the IDs of the trucks carrying each of its values
+type tagIndex map[string]map[string]map[string]struct{}
+
+// add records that truck id carries tags
+func (idx tagIndex) add(id string, tags map[string]string) {
+	for key, value := range tags {
+		values := idx[key]
+		if values == nil {
+			values = make(map[string]map[string]struct{})
+			idx[key] = values
+		}
+		ids := values[value]
+		if ids == nil {
+			ids = make(map[string]struct{})
+			values[value] = ids
+		}
+		ids[id] = struct{}{}
+	}
+}
+
+// remove forgets that truck id carries tags
+func (idx tagIndex) remove(id string, tags map[string]string) {
+	for key, value := range tags {
+		ids := idx[key][value]
+		delete(ids, id)
+		if len(ids) == 0 {
+			delete(idx[key], value)
+		}
+		if len(idx[key]) == 0 {
+			delete(idx, key)
+		}
+	}
+}
+
+// validateTags checks that no tag has an empty key
+func validateTags(tags map[string]string) error {
+	if _, exist := tags[""]; exist {
+		return ErrInvalidTag
+	}
+	return nil
+}
+
+// SetTag sets a tag on a truck, replacing any value the key already had. A
+// tag without a value, such as "refrigerated", is set with an empty value.
+func (tm *truckManager) SetTag(truckID, key, value string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+	if key == "" {
+		return ErrInvalidTag
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if current, set := truck.Tags[key]; set && current == value {
+		return nil
+	}
+	tags := maps.Clone(truck.Tags)
+	if tags == nil {
+		tags = make(map[string]string, 1)
+	}
+	tags[key] = value
+	return tm.setTagsLocked(truckID, tags)
+}
+
+// RemoveTag removes a tag from a truck. Removing a tag the truck doesn't carry does nothing.
+func (tm *truckManager) RemoveTag(truckID, key string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	if _, set := truck.Tags[key]; !set {
+		return nil
+	}
+	tags := maps.Clone(truck.Tags)
+	delete(tags, key)
+	if len(tags) == 0 {
+		tags = nil
+	}
+	return tm.setTagsLocked(truckID, tags)
+}
+
+// setTagsLocked replaces the tags of an existing truck and updates the
+// index. The map must not be shared with any other truck. Callers must hold
+// the write lock.
+func (tm *truckManager) setTagsLocked(truckID string, tags map[string]string) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.Tags = tags
+	updated.Version++
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+	old := *truck
+	tm.tags.remove(truckID, old.Tags)
+	tm.tags.add(truckID, updated.Tags)
+	tm.trucks[truckID].store(&updated)
+	tm.emit(TagsChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("tag", truckID, &old, &updated)
+	return nil
+}
+
+// GetTrucksByTag returns the trucks tagged with key set to value, ordered by
+// ID. An empty value matches every truck carrying the key, whatever its
+// value. The lookup goes through the tag index rather than the whole fleet.
+func (tm *truckManager) GetTrucksByTag(key, value string) []Truck {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	var ids []string
+	if value != "" {
+		for id := range tm.tags[key][value] {
+			ids = append(ids, id)
+		}
+	} else {
+		for _, tagged := range tm.tags[key] {
+			for id := range tagged {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+
+	trucks := make([]Truck, 0, len(ids))
+	for _, id := range ids {
+		trucks = append(trucks, tm.trucks[id].load().clone())
+	}
+	return trucks
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetTag(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.SetTag("1", "region", "west"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	manager.SetTag("1", "refrigerated", "")
+
+	truck, _ := manager.GetTruck("1")
+	if truck.Tags["region"] != "west" {
+		t.Errorf("Expected region west, got %q", truck.Tags["region"])
+	}
+	if _, ok := truck.Tags["refrigerated"]; !ok {
+		t.Errorf("Expected refrigerated tag, got %v", truck.Tags)
+	}
+	if truck.Version != 3 {
+		t.Errorf("Expected version 3, got %d", truck.Version)
+	}
+
+	// Setting the same value again changes nothing
+	manager.SetTag("1", "region", "west")
+	truck, _ = manager.GetTruck("1")
+	if truck.Version != 3 {
+		t.Errorf("Expected version to stay 3, got %d", truck.Version)
+	}
+}
+
+func TestSetTagErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.SetTag("", "region", "west"); err != ErrEmptyID {
+		t.Errorf("Expected ErrEmptyID, got %v", err)
+	}
+	if err := manager.SetTag("1", "", "west"); err != ErrInvalidTag {
+		t.Errorf("Expected ErrInvalidTag, got %v", err)
+	}
+	if err := manager.SetTag("x", "region", "west"); err != ErrTruckNotFound {
+		t.Errorf("Expected ErrTruckNotFound, got %v", err)
+	}
+	if err := manager.RemoveTag("x", "region"); err != ErrTruckNotFound {
+		t.Errorf("Expected ErrTruckNotFound, got %v", err)
+	}
+}
+
+func TestRemoveTag(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetTag("1", "region", "west")
+
+	if err := manager.RemoveTag("1", "region"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.Tags != nil {
+		t.Errorf("Expected no tags, got %v", truck.Tags)
+	}
+	if err := manager.RemoveTag("1", "region"); err != nil {
+		t.Errorf("Expected removing a missing tag to succeed, got %v", err)
+	}
+	if got := manager.GetTrucksByTag("region", ""); len(got) != 0 {
+		t.Errorf("Expected no tagged trucks, got %v", got)
+	}
+}
+
+func TestGetTrucksByTag(t *testing.T) {
+	manager := NewFleetManager()
+	for _, id := range []string{"c", "a", "b", "d"} {
+		manager.AddTruck(id, 100)
+	}
+	manager.SetTag("a", "region", "west")
+	manager.SetTag("c", "region", "west")
+	manager.SetTag("b", "region", "east")
+
+	west := manager.GetTrucksByTag("region", "west")
+	if len(west) != 2 || west[0].ID != "a" || west[1].ID != "c" {
+		t.Errorf("Expected trucks a and c, got %v", west)
+	}
+	if tagged := manager.GetTrucksByTag("region", ""); len(tagged) != 3 {
+		t.Errorf("Expected 3 trucks with a region, got %v", tagged)
+	}
+
+	// Changing or removing trucks keeps the index in step
+	manager.SetTag("a", "region", "east")
+	manager.RemoveTruck("c")
+	if west := manager.GetTrucksByTag("region", "west"); len(west) != 0 {
+		t.Errorf("Expected no western trucks, got %v", west)
+	}
+	east := manager.GetTrucksByTag("region", "east")
+	if len(east) != 2 || east[0].ID != "a" || east[1].ID != "b" {
+		t.Errorf("Expected trucks a and b, got %v", east)
+	}
+	if got := manager.GetTrucksByTag("missing", ""); len(got) != 0 {
+		t.Errorf("Expected no trucks, got %v", got)
+	}
+}
+
+func TestTagsAreCopied(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.SetTag("1", "region", "west")
+
+	truck, _ := manager.GetTruck("1")
+	truck.Tags["region"] = "east"
+
+	truck, _ = manager.GetTruck("1")
+	if truck.Tags["region"] != "west" {
+		t.Errorf("Expected region to stay west, got %q", truck.Tags["region"])
+	}
+}
+
+func TestAddTruckWithEmptyTagKey(t *testing.T) {
+	manager := NewFleetManager()
+	_, err := manager.AddTrucks([]Truck{{ID: "1", Capacity: 10, Tags: map[string]string{"": "x"}}})
+	if err != nil {
+		t.Fatalf("Expected no batch error, got %v", err)
+	}
+	if _, err := manager.GetTruck("1"); !errors.Is(err, ErrTruckNotFound) {
+		t.Errorf("Expected truck with an empty tag key to be rejected, got %v", err)
+	}
+}
+
+func TestTagsSurviveStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	manager, _ := OpenTruckManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+	manager.SetTag("1", "region", "west")
+
+	reopened, err := OpenTruckManager(WithStorage(storage))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := reopened.GetTrucksByTag("region", "west"); len(got) != 1 || got[0].ID != "1" {
+		t.Errorf("Expected truck 1 after reload, got %v", got)
+	}
+}
+
+func TestImportMergesTags(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	input := `{"trucks": [{"id": "1", "capacity": 100, "tags": {"region": "west"}}, {"id": "2", "capacity": 50, "tags": {"region": "west"}}]}`
+	if _, err := manager.ImportFleet(strings.NewReader(input), FormatJSON, MergeUpdate); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := manager.GetTrucksByTag("region", "west"); len(got) != 2 {
+		t.Errorf("Expected 2 western trucks, got %v", got)
+	}
+}
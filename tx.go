@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrTxDone is returned when a transaction is used after Commit or Rollback
+var ErrTxDone = errors.New("transaction already committed or rolled back")
+
+// Tx collects changes to apply to the fleet atomically. Nothing is applied
+// until Commit, which applies every change under one write lock, so other
+// callers see the fleet either before or after the whole transaction. If
+// any change fails, the ones before it are undone and Commit returns an
+// error wrapping ErrBatchAborted. A Tx is safe for concurrent use.
+type Tx struct {
+	tm    *truckManager
+	mu    sync.Mutex
+	items []batchItem
+	done  bool
+}
+
+// Begin starts a transaction on the fleet
+func (tm *truckManager) Begin() *Tx {
+	return &Tx{tm: tm}
+}
+
+// queue adds an item unless the transaction is finished
+func (tx *Tx) queue(item batchItem) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.items = append(tx.items, item)
+	return nil
+}
+
+// AddTruck queues adding a truck
+func (tx *Tx) AddTruck(id string, capacity int) error {
+	return tx.queue(tx.tm.addItem(Truck{ID: id, Capacity: capacity}))
+}
+
+// RemoveTruck queues removing a truck
+func (tx *Tx) RemoveTruck(id string) error {
+	return tx.queue(tx.tm.removeItem(id))
+}
+
+// UpdateTruckCargo queues setting a truck's cargo capacity
+func (tx *Tx) UpdateTruckCargo(id string, capacity int) error {
+	return tx.queue(tx.tm.updateItem(id, func(Truck) (UpdateSpec, error) {
+		return NewUpdateSpec().WithCapacity(capacity), nil
+	}))
+}
+
+// LoadCargo queues loading amount onto a truck. The room left is checked
+// against the truck as earlier changes in the transaction leave it.
+func (tx *Tx) LoadCargo(id string, amount int) error {
+	if amount <= 0 {
+		return ErrInvalidCargo
+	}
+	return tx.queue(tx.tm.moveItem(id, amount))
+}
+
+// UnloadCargo queues unloading amount from a truck
+func (tx *Tx) UnloadCargo(id string, amount int) error {
+	if amount <= 0 {
+		return ErrInvalidCargo
+	}
+	return tx.queue(tx.tm.moveItem(id, -amount))
+}
+
+// moveItem is a batch item changing a truck's load by delta, audited as a
+// load or unload
+func (tm *truckManager) moveItem(id string, delta int) batchItem {
+	item := tm.updateItem(id, func(truck Truck) (UpdateSpec, error) {
+		if delta > 0 && truck.CurrentLoad+delta > truck.Capacity {
+			return UpdateSpec{}, ErrCapacityExceeded
+		}
+		if delta < 0 && truck.CurrentLoad+delta < 0 {
+			return UpdateSpec{}, ErrInsufficientCargo
+		}
+		return NewUpdateSpec().WithCurrentLoad(truck.CurrentLoad + delta), nil
+	})
+	item.op = "load"
+	if delta < 0 {
+		item.op = "unload"
+	}
+	return item
+}
+
+// Commit applies every queued change atomically and finishes the transaction
+func (tx *Tx) Commit() error {
+	tx.mu.Lock()
+	if tx.done {
+		tx.mu.Unlock()
+		return ErrTxDone
+	}
+	tx.done = true
+	items := tx.items
+	tx.items = nil
+	tx.mu.Unlock()
+
+	_, err := tx.tm.applyBatch("tx", items, []BatchOption{AllOrNothing()})
+	return err
+}
+
+// Rollback discards every queued change and finishes the transaction.
+// Rolling back a finished transaction returns ErrTxDone.
+func (tx *Tx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.items = nil
+	return nil
+}
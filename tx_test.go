@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTxCommit(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	tx := manager.Begin()
+	tx.AddTruck("2", 200)
+	tx.UpdateTruckCargo("1", 150)
+	tx.LoadCargo("1", 120)
+	tx.LoadCargo("2", 50)
+	tx.UnloadCargo("2", 20)
+
+	// Nothing is applied before Commit
+	if _, err := manager.GetTruck("2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck 2 to be absent before commit, got %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	one, _ := manager.GetTruck("1")
+	two, _ := manager.GetTruck("2")
+	if one.Capacity != 150 || one.CurrentLoad != 120 || two.CurrentLoad != 30 {
+		t.Errorf("Expected 120/150 and 30/200, got %+v and %+v", one, two)
+	}
+}
+
+func TestTxAuditsEachOp(t *testing.T) {
+	manager := NewFleetManager(WithAuditLog(NewAuditLog(nil)))
+	manager.AddTruck("1", 100)
+
+	tx := manager.Begin()
+	tx.AddTruck("2", 200)
+	tx.UpdateTruckCargo("1", 150)
+	tx.LoadCargo("1", 120)
+	tx.UnloadCargo("1", 20)
+	tx.RemoveTruck("2")
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	entries, _ := manager.GetAuditLog("", time.Time{}, 0, 10)
+	ops := []string{"add", "add", "update", "load", "unload", "remove"}
+	if len(entries) != len(ops) {
+		t.Fatalf("Expected %d entries, got %+v", len(ops), entries)
+	}
+	for i, e := range entries {
+		if e.Op != ops[i] {
+			t.Errorf("Expected entry %d to be %s, got %s", i, ops[i], e.Op)
+		}
+	}
+}
+
+func TestTxRejectsInvalidAmount(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("1", 50)
+
+	tx := manager.Begin()
+	for _, amount := range []int{0, -5} {
+		if err := tx.LoadCargo("1", amount); !errors.Is(err, ErrInvalidCargo) {
+			t.Errorf("Expected ErrInvalidCargo loading %d, got %v", amount, err)
+		}
+		if err := tx.UnloadCargo("1", amount); !errors.Is(err, ErrInvalidCargo) {
+			t.Errorf("Expected ErrInvalidCargo unloading %d, got %v", amount, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if truck, _ := manager.GetTruck("1"); truck.CurrentLoad != 50 {
+		t.Errorf("Expected load unchanged at 50, got %d", truck.CurrentLoad)
+	}
+}
+
+func TestTxCommitFailureRollsBack(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.LoadCargo("1", 10)
+
+	tx := manager.Begin()
+	tx.AddTruck("2", 200)
+	tx.LoadCargo("1", 50)
+	tx.RemoveTruck("1")
+	tx.LoadCargo("missing", 1)
+
+	if err := tx.Commit(); !errors.Is(err, ErrBatchAborted) {
+		t.Fatalf("Expected ErrBatchAborted, got %v", err)
+	}
+	if _, err := manager.GetTruck("2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck 2 to be rolled back, got %v", err)
+	}
+	truck, err := manager.GetTruck("1")
+	if err != nil || truck.CurrentLoad != 10 {
+		t.Errorf("Expected truck 1 restored with load 10, got %+v and %v", truck, err)
+	}
+}
+
+func TestTxLoadSeesEarlierChanges(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	tx := manager.Begin()
+	tx.UpdateTruckCargo("1", 300)
+	tx.LoadCargo("1", 250)
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Expected the load to fit the new capacity, got %v", err)
+	}
+
+	tx = manager.Begin()
+	tx.LoadCargo("1", 100)
+	if err := tx.Commit(); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded, got %v", err)
+	}
+}
+
+func TestTxRollback(t *testing.T) {
+	manager := NewFleetManager()
+	tx := manager.Begin()
+	tx.AddTruck("1", 100)
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := manager.GetTruck("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected nothing applied, got %v", err)
+	}
+	if err := tx.AddTruck("2", 100); err != ErrTxDone {
+		t.Errorf("Expected ErrTxDone, got %v", err)
+	}
+	if err := tx.Commit(); err != ErrTxDone {
+		t.Errorf("Expected ErrTxDone, got %v", err)
+	}
+	if err := tx.Rollback(); err != ErrTxDone {
+		t.Errorf("Expected ErrTxDone, got %v", err)
+	}
+}
+
+func TestTxEventsOnlyAfterCommit(t *testing.T) {
+	manager := NewFleetManager()
+	events := make(chan FleetEvent, 10)
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	tx := manager.Begin()
+	tx.AddTruck("1", 100)
+	tx.AddTruck("1", 100)
+	tx.Commit()
+
+	manager.AddTruck("2", 100)
+	ev := <-events
+	if ev.TruckID != "2" {
+		t.Errorf("Expected only the event for truck 2, got %+v", ev)
+	}
+}
+
+func TestTxReadersSeeNoPartialState(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("a", 1000)
+	manager.AddTruck("b", 1000)
+	manager.LoadCargo("a", 500)
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for !stop.Load() {
+			page, _ := manager.ListTrucks(0, 2)
+			if total := page[0].CurrentLoad + page[1].CurrentLoad; total != 500 {
+				t.Errorf("Expected 500 on board in total, got %d", total)
+				return
+			}
+		}
+	}()
+
+	// Move cargo back and forth between the trucks
+	for i := 0; i < 200; i++ {
+		from, to := "a", "b"
+		if i%2 == 1 {
+			from, to = to, from
+		}
+		tx := manager.Begin()
+		tx.UnloadCargo(from, 500)
+		tx.LoadCargo(to, 500)
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// File names inside a WALStorage directory
+const (
+	walLogFile      = "wal.log"
+	walSnapshotFile = "snapshot.json"
+)
+
+// ErrCorruptLog is returned when a write-ahead log entry other than the
+// last one cannot be decoded
+var ErrCorruptLog = errors.New("corrupt write-ahead log")
+
+// walOp is the kind of mutation a log entry records
+type walOp string
+
+const (
+	walSave          walOp = "save"
+	walDelete        walOp = "delete"
+	walSaveRemoved   walOp = "save_removed"
+	walDeleteRemoved walOp = "delete_removed"
+)
+
+// walEntry is one line of the log. Seq increases by one per entry and
+// carries on across compactions.
+type walEntry struct {
+	Seq   uint64 `json:"seq"`
+	Op    walOp  `json:"op"`
+	ID    string `json:"id"`
+	Truck *Truck `json:"truck,omitempty"`
+	// Removed is the recycle bin entry of a save_removed entry
+	Removed *RemovedTruck `json:"removed,omitempty"`
+}
+
+// walSnapshot is the on-disk layout of a compacted fleet. Seq is the last
+// log entry it includes.
+type walSnapshot struct {
+	Seq     uint64         `json:"seq"`
+	Trucks  []Truck        `json:"trucks"`
+	Removed []RemovedTruck `json:"removed,omitempty"`
+}
+
+// WALStorage is a Storage that appends every mutation to a log and syncs it
+// before returning, so a mutation that returned is never lost, even if the
+// process is killed. Each change costs one small append rather than
+// rewriting the whole fleet as JSONFileStorage does. The log is folded into
+// a snapshot by Compact, or automatically once SetCompactEvery is set.
+type WALStorage struct {
+	dir     string
+	log     *os.File
+	size    int64 // bytes of the log known to be intact
+	seq     uint64
+	entries int // entries in the log since the last snapshot
+	trucks  map[string]Truck
+	removed map[string]RemovedTruck
+	// compactEvery triggers Compact once the log holds this many entries; 0 disables it
+	compactEvery int
+	// compactErr is the last automatic compaction failure; see CompactErr
+	compactErr error
+	sync.RWMutex
+}
+
+// NewWALStorage opens the write-ahead log in dir, creating the directory if
+// needed, and recovers the fleet from the snapshot and the entries logged
+// after it. A torn final entry, left by a crash mid-append, is discarded.
+func NewWALStorage(dir string) (*WALStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &WALStorage{dir: dir, trucks: make(map[string]Truck), removed: make(map[string]RemovedTruck)}
+	if err := s.readSnapshot(); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, walLogFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s.log = log
+	if err := s.replay(); err != nil {
+		log.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetCompactEvery makes the storage compact itself once the log holds n
+// entries. Zero, the default, leaves compaction to the caller.
+func (s *WALStorage) SetCompactEvery(n int) {
+	s.Lock()
+	defer s.Unlock()
+	s.compactEvery = n
+}
+
+// Save logs the truck and applies it
+func (s *WALStorage) Save(t Truck) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.append(walEntry{Op: walSave, ID: t.ID, Truck: &t}); err != nil {
+		return err
+	}
+	s.trucks[t.ID] = t
+	s.maybeCompact()
+	return nil
+}
+
+// Load returns the stored truck with the given ID
+func (s *WALStorage) Load(id string) (Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	t, exist := s.trucks[id]
+	if !exist {
+		return Truck{}, ErrTruckNotFound
+	}
+	return t, nil
+}
+
+// Delete logs the removal and applies it
+func (s *WALStorage) Delete(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exist := s.trucks[id]; !exist {
+		return ErrTruckNotFound
+	}
+	if err := s.append(walEntry{Op: walDelete, ID: id}); err != nil {
+		return err
+	}
+	delete(s.trucks, id)
+	s.maybeCompact()
+	return nil
+}
+
+// List returns all stored trucks ordered by ID
+func (s *WALStorage) List() ([]Truck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedTrucks(s.trucks), nil
+}
+
+// SaveRemoved logs a recycle bin entry and applies it
+func (s *WALStorage) SaveRemoved(r RemovedTruck) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.append(walEntry{Op: walSaveRemoved, ID: r.Truck.ID, Removed: &r}); err != nil {
+		return err
+	}
+	s.removed[r.Truck.ID] = r
+	s.maybeCompact()
+	return nil
+}
+
+// DeleteRemoved logs the removal of a recycle bin entry and applies it
+func (s *WALStorage) DeleteRemoved(id string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exist := s.removed[id]; !exist {
+		return ErrTruckNotRemoved
+	}
+	if err := s.append(walEntry{Op: walDeleteRemoved, ID: id}); err != nil {
+		return err
+	}
+	delete(s.removed, id)
+	s.maybeCompact()
+	return nil
+}
+
+// ListRemoved returns the recycle bin entries ordered by truck ID
+func (s *WALStorage) ListRemoved() ([]RemovedTruck, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return sortedRemoved(s.removed), nil
+}
+
+// Compact writes the current fleet to a snapshot and empties the log
+func (s *WALStorage) Compact() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.compact()
+}
+
+// CompactErr returns the error of the last automatic compaction, or nil if
+// it succeeded. A failed compaction doesn't fail the change that triggered
+// it, since that change is already in the log; the next change or Compact
+// tries again.
+func (s *WALStorage) CompactErr() error {
+	s.RLock()
+	defer s.RUnlock()
+	return s.compactErr
+}
+
+// Close closes the log file
+func (s *WALStorage) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.log.Close()
+}
+
+// append writes and syncs one entry. On failure the log is cut back to its
+// last intact entry so later appends don't follow a partial line. Callers
+// must hold the write lock.
+func (s *WALStorage) append(e walEntry) error {
+	e.Seq = s.seq + 1
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if _, err := s.log.WriteAt(line, s.size); err != nil {
+		s.log.Truncate(s.size)
+		return err
+	}
+	if err := s.log.Sync(); err != nil {
+		s.log.Truncate(s.size)
+		return err
+	}
+	s.size += int64(len(line))
+	s.seq = e.Seq
+	s.entries++
+	return nil
+}
+
+// maybeCompact compacts once the log reaches the configured size, keeping
+// any failure for CompactErr. Callers must hold the write lock.
+func (s *WALStorage) maybeCompact() {
+	if s.compactEvery <= 0 || s.entries < s.compactEvery {
+		return
+	}
+	s.compactErr = s.compact()
+}
+
+// compact is Compact for callers already holding the write lock. The
+// snapshot records the last sequence number it covers, so a crash between
+// writing it and truncating the log replays nothing twice.
+func (s *WALStorage) compact() error {
+	data, err := json.MarshalIndent(walSnapshot{Seq: s.seq, Trucks: sortedTrucks(s.trucks), Removed: sortedRemoved(s.removed)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, walSnapshotFile), data); err != nil {
+		return err
+	}
+	if err := s.log.Truncate(0); err != nil {
+		return err
+	}
+	s.size = 0
+	s.entries = 0
+	if err := s.log.Sync(); err != nil {
+		return err
+	}
+	s.compactErr = nil
+	return nil
+}
+
+// readSnapshot loads the snapshot, if there is one
+func (s *WALStorage) readSnapshot() error {
+	path := filepath.Join(s.dir, walSnapshotFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot walSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("reading snapshot %s: %w", path, err)
+	}
+	for _, t := range snapshot.Trucks {
+		s.trucks[t.ID] = t
+	}
+	for _, r := range snapshot.Removed {
+		s.removed[r.Truck.ID] = r
+	}
+	s.seq = snapshot.Seq
+	return nil
+}
+
+// replay applies the log entries the snapshot doesn't cover
+func (s *WALStorage) replay() error {
+	r := bufio.NewReader(s.log)
+	var offset int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A final line without its newline was never fully written
+			if len(bytes.TrimSpace(line)) > 0 {
+				return s.log.Truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var e walEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if _, peekErr := r.Peek(1); peekErr == io.EOF {
+				// Torn final entry: drop it
+				s.size = offset
+				return s.log.Truncate(offset)
+			}
+			return fmt.Errorf("%w: entry at byte %d: %v", ErrCorruptLog, offset, err)
+		}
+		offset += int64(len(line))
+		s.size = offset
+
+		if e.Seq <= s.seq {
+			continue
+		}
+		switch e.Op {
+		case walSave:
+			if e.Truck == nil {
+				return fmt.Errorf("%w: save entry %d has no truck", ErrCorruptLog, e.Seq)
+			}
+			s.trucks[e.ID] = *e.Truck
+		case walDelete:
+			delete(s.trucks, e.ID)
+		case walSaveRemoved:
+			if e.Removed == nil {
+				return fmt.Errorf("%w: save_removed entry %d has no truck", ErrCorruptLog, e.Seq)
+			}
+			s.removed[e.ID] = *e.Removed
+		case walDeleteRemoved:
+			delete(s.removed, e.ID)
+		default:
+			return fmt.Errorf("%w: entry %d has unknown op %q", ErrCorruptLog, e.Seq, e.Op)
+		}
+		s.seq = e.Seq
+		s.entries++
+	}
+	return nil
+}
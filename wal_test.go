@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWALStorageRecovers(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewWALStorage(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	manager, _ := OpenTruckManager(WithStorage(storage))
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 200)
+	manager.LoadCargo("2", 50)
+	manager.RemoveTruck("1")
+
+	// Simulate a crash: no Close, no Compact
+	recovered, err := NewWALStorage(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	restarted, _ := OpenTruckManager(WithStorage(recovered))
+	if restarted.Exists("1") {
+		t.Errorf("Expected truck 1 to stay removed")
+	}
+	truck, err := restarted.GetTruck("2")
+	if err != nil || truck.CurrentLoad != 50 {
+		t.Errorf("Expected truck 2 with load 50, got %+v, %v", truck, err)
+	}
+}
+
+func TestWALStorageDropsTornEntry(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.Save(Truck{ID: "1", Capacity: 100})
+	storage.Close()
+
+	log, _ := os.OpenFile(filepath.Join(dir, walLogFile), os.O_APPEND|os.O_WRONLY, 0o644)
+	log.WriteString(`{"seq":2,"op":"save","id":"2","tru`)
+	log.Close()
+
+	recovered, err := NewWALStorage(dir)
+	if err != nil {
+		t.Fatalf("Expected a torn final entry to be ignored, got %v", err)
+	}
+	if _, err := recovered.Load("2"); err != ErrTruckNotFound {
+		t.Errorf("Expected the torn truck to be discarded, got %v", err)
+	}
+
+	// The log keeps working after the torn entry was cut off
+	recovered.Save(Truck{ID: "3", Capacity: 300})
+	recovered.Close()
+	again, _ := NewWALStorage(dir)
+	trucks, _ := again.List()
+	if len(trucks) != 2 || trucks[1].ID != "3" {
+		t.Errorf("Expected trucks 1 and 3, got %+v", trucks)
+	}
+}
+
+func TestWALStorageRejectsCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, walLogFile), []byte("not json\n{\"seq\":1,\"op\":\"delete\",\"id\":\"1\"}\n"), 0o644)
+
+	if _, err := NewWALStorage(dir); !errors.Is(err, ErrCorruptLog) {
+		t.Errorf("Expected corrupt log error, got %v", err)
+	}
+}
+
+func TestWALStorageCompact(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.Save(Truck{ID: "1", Capacity: 100})
+	storage.Save(Truck{ID: "2", Capacity: 200})
+	storage.Delete("1")
+
+	if err := storage.Compact(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, walLogFile)); info.Size() != 0 {
+		t.Errorf("Expected an empty log after compaction, got %d bytes", info.Size())
+	}
+
+	storage.Save(Truck{ID: "3", Capacity: 300})
+	storage.Close()
+
+	recovered, _ := NewWALStorage(dir)
+	trucks, _ := recovered.List()
+	if len(trucks) != 2 || trucks[0].ID != "2" || trucks[1].ID != "3" {
+		t.Errorf("Expected trucks 2 and 3 from snapshot and log, got %+v", trucks)
+	}
+}
+
+func TestWALStorageSkipsEntriesInSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.Save(Truck{ID: "1", Capacity: 100})
+	storage.Close()
+	log, _ := os.ReadFile(filepath.Join(dir, walLogFile))
+
+	// A crash after the snapshot was written but before the log was emptied
+	again, _ := NewWALStorage(dir)
+	again.Compact()
+	again.Delete("1")
+	again.Compact()
+	again.Close()
+	os.WriteFile(filepath.Join(dir, walLogFile), log, 0o644)
+
+	recovered, _ := NewWALStorage(dir)
+	if _, err := recovered.Load("1"); err != ErrTruckNotFound {
+		t.Errorf("Expected the stale entry to be skipped, got %v", err)
+	}
+}
+
+func TestWALStorageCompactEvery(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.SetCompactEvery(2)
+
+	storage.Save(Truck{ID: "1", Capacity: 100})
+	storage.Save(Truck{ID: "2", Capacity: 200})
+	if _, err := os.Stat(filepath.Join(dir, walSnapshotFile)); err != nil {
+		t.Errorf("Expected a snapshot after 2 entries, got %v", err)
+	}
+}
+
+func TestWALStorageCompactFailureKeepsChange(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.SetCompactEvery(1)
+	// A directory where the snapshot goes makes the rename in compact fail
+	snapshot := filepath.Join(dir, walSnapshotFile)
+	os.Mkdir(snapshot, 0o755)
+
+	if err := storage.Save(Truck{ID: "1", Capacity: 100}); err != nil {
+		t.Fatalf("Expected the logged change to succeed, got %v", err)
+	}
+	if storage.CompactErr() == nil {
+		t.Errorf("Expected the failed compaction to be kept")
+	}
+	if truck, err := storage.Load("1"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck 1 applied, got %+v, %v", truck, err)
+	}
+
+	storage.Close()
+	os.Remove(snapshot)
+	recovered, err := NewWALStorage(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if truck, err := recovered.Load("1"); err != nil || truck.Capacity != 100 {
+		t.Errorf("Expected truck 1 after reopening, got %+v, %v", truck, err)
+	}
+	if err := recovered.Compact(); err != nil || recovered.CompactErr() != nil {
+		t.Errorf("Expected compaction to succeed once possible, got %v", err)
+	}
+}
+
+func TestWALStorageKeepsRecycleBin(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewWALStorage(dir)
+	storage.SaveRemoved(RemovedTruck{Truck: Truck{ID: "1", Capacity: 100}})
+	storage.SaveRemoved(RemovedTruck{Truck: Truck{ID: "2", Capacity: 200}})
+	storage.Compact()
+	storage.DeleteRemoved("2")
+
+	recovered, err := NewWALStorage(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	removed, _ := recovered.ListRemoved()
+	if len(removed) != 1 || removed[0].Truck.ID != "1" || removed[0].Truck.Capacity != 100 {
+		t.Errorf("Expected truck 1 in the recovered recycle bin, got %+v", removed)
+	}
+	if err := recovered.DeleteRemoved("2"); !errors.Is(err, ErrTruckNotRemoved) {
+		t.Errorf("Expected not removed error, got %v", err)
+	}
+}
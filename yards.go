@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Errors for yard management
+var (
+	ErrYardNotFound   = errors.New("yard not found")
+	ErrYardExist      = errors.New("yard already exist")
+	ErrInvalidYard    = errors.New("invalid yard")
+	ErrAlreadyInYard  = errors.New("truck already checked in to a yard")
+	ErrNotInYard      = errors.New("truck is not in a yard")
+	ErrSlotTaken      = errors.New("parking slot taken")
+	ErrSlotOutOfRange = errors.New("parking slot out of range")
+)
+
+// Yard is a depot yard with a fixed number of parking slots, numbered from 1
+type Yard struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Slots int    `json:"slots"`
+}
+
+// GateDirection tells whether a truck entered or left a yard
+type GateDirection string
+
+// Gate directions
+const (
+	GateIn  GateDirection = "in"
+	GateOut GateDirection = "out"
+)
+
+// GateEvent is one pass through a yard gate. Slot is 0 for a truck admitted
+// with every slot taken.
+type GateEvent struct {
+	TruckID   string
+	Direction GateDirection
+	Slot      int
+	Time      time.Time
+}
+
+// ParkedTruck is a truck present in a yard and the slot it occupies, 0 if none
+type ParkedTruck struct {
+	TruckID string
+	Slot    int
+}
+
+// YardOccupancy reports who is in a yard
+type YardOccupancy struct {
+	Yard     Yard
+	Occupied int           // trucks holding a slot
+	Overflow int           // trucks in the yard without a slot
+	Trucks   []ParkedTruck // ordered by slot, overflow last, then by truck ID
+}
+
+// yard is the manager's record of a yard and what is in it
+type yard struct {
+	Yard
+	slots   map[int]string // slot -> truck ID
+	present map[string]int // truck ID -> slot, 0 for overflow
+	gate    []GateEvent
+}
+
+// freeSlot returns the lowest free slot, or 0 if the yard is full
+func (y *yard) freeSlot() int {
+	for slot := 1; slot <= y.Slots; slot++ {
+		if _, taken := y.slots[slot]; !taken {
+			return slot
+		}
+	}
+	return 0
+}
+
+// admit records truckID as present, in the lowest free slot if there is one
+func (y *yard) admit(truckID string) int {
+	slot := y.freeSlot()
+	if slot != 0 {
+		y.slots[slot] = truckID
+	}
+	y.present[truckID] = slot
+	return slot
+}
+
+// release forgets truckID and frees its slot, returning the slot it held
+func (y *yard) release(truckID string) int {
+	slot := y.present[truckID]
+	delete(y.present, truckID)
+	if slot != 0 {
+		delete(y.slots, slot)
+	}
+	return slot
+}
+
+// AddYard registers a yard with the given number of parking slots. Yards
+// are held in memory only; the configured Storage does not persist them.
+func (tm *truckManager) AddYard(y Yard) error {
+	if y.ID == "" {
+		return ErrEmptyID
+	}
+	if y.Slots < 0 {
+		return fmt.Errorf("%w: slots must not be negative", ErrInvalidYard)
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	if _, exist := tm.yards[y.ID]; exist {
+		return ErrYardExist
+	}
+	tm.yards[y.ID] = &yard{Yard: y, slots: make(map[int]string), present: make(map[string]int)}
+	return nil
+}
+
+// CheckIn records a truck entering a yard through the gate and parks it in
+// the lowest free slot. A full yard still admits the truck, without a slot,
+// and publishes a YardOverCapacity event so the overflow can be dealt with.
+func (tm *truckManager) CheckIn(yardID, truckID string) (int, error) {
+	if yardID == "" || truckID == "" {
+		return 0, ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return 0, ErrTruckNotFound
+	}
+	y, exist := tm.yards[yardID]
+	if !exist {
+		return 0, ErrYardNotFound
+	}
+	if truck.YardID != "" {
+		return 0, ErrAlreadyInYard
+	}
+	if err := tm.setYardLocked(truckID, yardID); err != nil {
+		return 0, err
+	}
+
+	slot := y.admit(truckID)
+	y.gate = append(y.gate, GateEvent{TruckID: truckID, Direction: GateIn, Slot: slot, Time: time.Now()})
+	if slot == 0 {
+		// Report the truck as it is now, parked in the yard
+		admitted, _ := tm.truck(truckID)
+		tm.emit(YardOverCapacity, truckID, nil, admitted)
+	}
+	return slot, nil
+}
+
+// CheckOut records a truck leaving its yard through the gate and frees its slot
+func (tm *truckManager) CheckOut(truckID string) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	y, exist := tm.yards[truck.YardID]
+	if !exist {
+		return ErrNotInYard
+	}
+	if err := tm.setYardLocked(truckID, ""); err != nil {
+		return err
+	}
+
+	slot := y.release(truckID)
+	y.gate = append(y.gate, GateEvent{TruckID: truckID, Direction: GateOut, Slot: slot, Time: time.Now()})
+	return nil
+}
+
+// AssignSlot moves a truck already in a yard to another free slot there.
+// Trucks admitted as overflow use this to take a slot once one frees up.
+func (tm *truckManager) AssignSlot(truckID string, slot int) error {
+	if truckID == "" {
+		return ErrEmptyID
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+
+	truck, exist := tm.truck(truckID)
+	if !exist {
+		return ErrTruckNotFound
+	}
+	y, exist := tm.yards[truck.YardID]
+	if !exist {
+		return ErrNotInYard
+	}
+	if slot < 1 || slot > y.Slots {
+		return ErrSlotOutOfRange
+	}
+	if holder, taken := y.slots[slot]; taken {
+		if holder == truckID {
+			return nil
+		}
+		return ErrSlotTaken
+	}
+
+	y.release(truckID)
+	y.slots[slot] = truckID
+	y.present[truckID] = slot
+	return nil
+}
+
+// Occupancy reports which trucks are in a yard and where they are parked
+func (tm *truckManager) Occupancy(yardID string) (YardOccupancy, error) {
+	if yardID == "" {
+		return YardOccupancy{}, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	y, exist := tm.yards[yardID]
+	if !exist {
+		return YardOccupancy{}, ErrYardNotFound
+	}
+
+	occupancy := YardOccupancy{Yard: y.Yard, Trucks: make([]ParkedTruck, 0, len(y.present))}
+	for truckID, slot := range y.present {
+		occupancy.Trucks = append(occupancy.Trucks, ParkedTruck{TruckID: truckID, Slot: slot})
+		if slot == 0 {
+			occupancy.Overflow++
+		} else {
+			occupancy.Occupied++
+		}
+	}
+	sort.Slice(occupancy.Trucks, func(i, j int) bool {
+		a, b := occupancy.Trucks[i], occupancy.Trucks[j]
+		if (a.Slot == 0) != (b.Slot == 0) {
+			return b.Slot == 0
+		}
+		if a.Slot != b.Slot {
+			return a.Slot < b.Slot
+		}
+		return a.TruckID < b.TruckID
+	})
+	return occupancy, nil
+}
+
+// GateLog returns a yard's gate events, oldest first
+func (tm *truckManager) GateLog(yardID string) ([]GateEvent, error) {
+	if yardID == "" {
+		return nil, ErrEmptyID
+	}
+
+	tm.RLock()
+	defer tm.RUnlock()
+
+	y, exist := tm.yards[yardID]
+	if !exist {
+		return nil, ErrYardNotFound
+	}
+	return append([]GateEvent(nil), y.gate...), nil
+}
+
+// setYardLocked changes the yard recorded on an existing truck. Callers must hold the write lock.
+func (tm *truckManager) setYardLocked(truckID, yardID string) error {
+	truck, _ := tm.truck(truckID)
+	updated := truck.clone()
+	updated.Version++
+	updated.YardID = yardID
+	if err := tm.persist(updated); err != nil {
+		return err
+	}
+
+	old := *truck
+	tm.trucks[truckID].store(&updated)
+	tm.emit(YardChanged, truckID, &old, &updated)
+	tm.auditChangeLocked("yard", truckID, &old, &updated)
+	return nil
+}
+
+// claimYardLocked puts a truck that is about to be added back into the yard
+// it names, in the lowest free slot, without a gate event. A yard the manager
+// doesn't know is dropped. Callers must hold the write lock.
+func (tm *truckManager) claimYardLocked(truck *Truck) {
+	if truck.YardID == "" {
+		return
+	}
+	y, exist := tm.yards[truck.YardID]
+	if !exist {
+		truck.YardID = ""
+		return
+	}
+	y.admit(truck.ID)
+}
+
+// releaseYardLocked frees the slot of a truck that is leaving the fleet.
+// Callers must hold the write lock.
+func (tm *truckManager) releaseYardLocked(truck *Truck) {
+	if y, exist := tm.yards[truck.YardID]; exist {
+		y.release(truck.ID)
+	}
+}
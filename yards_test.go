@@ -0,0 +1,128 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCheckInAndOut(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddYard(Yard{ID: "y1", Name: "North depot", Slots: 2})
+
+	slot, err := manager.CheckIn("y1", "1")
+	if err != nil || slot != 1 {
+		t.Fatalf("Expected slot 1, got %d, %v", slot, err)
+	}
+	if slot, _ := manager.CheckIn("y1", "2"); slot != 2 {
+		t.Errorf("Expected slot 2, got %d", slot)
+	}
+	if _, err := manager.CheckIn("y1", "1"); err != ErrAlreadyInYard {
+		t.Errorf("Expected already in yard error, got %v", err)
+	}
+	truck, _ := manager.GetTruck("1")
+	if truck.YardID != "y1" {
+		t.Errorf("Expected truck 1 in yard y1, got %q", truck.YardID)
+	}
+
+	if err := manager.CheckOut("1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := manager.CheckOut("1"); err != ErrNotInYard {
+		t.Errorf("Expected not in yard error, got %v", err)
+	}
+
+	log, _ := manager.GateLog("y1")
+	if len(log) != 3 || log[2].TruckID != "1" || log[2].Direction != GateOut || log[2].Slot != 1 {
+		t.Errorf("Expected three gate events ending with truck 1 leaving slot 1, got %+v", log)
+	}
+}
+
+func TestCheckInOverCapacity(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddYard(Yard{ID: "y1", Slots: 1})
+	manager.CheckIn("y1", "1")
+
+	events := make(chan FleetEvent, 4)
+	manager.Subscribe(events)
+	defer manager.Unsubscribe(events)
+
+	slot, err := manager.CheckIn("y1", "2")
+	if err != nil || slot != 0 {
+		t.Fatalf("Expected truck 2 admitted without a slot, got %d, %v", slot, err)
+	}
+	if ev := nextEvent(t, events); ev.Type != YardChanged {
+		t.Errorf("Expected yard changed event, got %s", ev.Type)
+	}
+	if ev := nextEvent(t, events); ev.Type != YardOverCapacity || ev.TruckID != "2" || ev.New.YardID != "y1" {
+		t.Errorf("Expected over capacity alert for truck 2 in yard y1, got %+v", ev)
+	}
+
+	occupancy, _ := manager.Occupancy("y1")
+	if occupancy.Occupied != 1 || occupancy.Overflow != 1 {
+		t.Errorf("Expected 1 occupied and 1 overflow, got %+v", occupancy)
+	}
+
+	// Once a slot frees up the overflow truck can take it
+	manager.CheckOut("1")
+	if err := manager.AssignSlot("2", 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	occupancy, _ = manager.Occupancy("y1")
+	if occupancy.Occupied != 1 || occupancy.Overflow != 0 || occupancy.Trucks[0].TruckID != "2" {
+		t.Errorf("Expected truck 2 in slot 1, got %+v", occupancy)
+	}
+}
+
+func TestAssignSlotErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddTruck("2", 100)
+	manager.AddYard(Yard{ID: "y1", Slots: 2})
+	manager.CheckIn("y1", "1")
+
+	if err := manager.AssignSlot("2", 2); err != ErrNotInYard {
+		t.Errorf("Expected not in yard error, got %v", err)
+	}
+	manager.CheckIn("y1", "2")
+	if err := manager.AssignSlot("2", 1); err != ErrSlotTaken {
+		t.Errorf("Expected slot taken error, got %v", err)
+	}
+	if err := manager.AssignSlot("2", 3); err != ErrSlotOutOfRange {
+		t.Errorf("Expected slot out of range error, got %v", err)
+	}
+}
+
+func TestRemoveTruckFreesSlot(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+	manager.AddYard(Yard{ID: "y1", Slots: 1})
+	manager.CheckIn("y1", "1")
+
+	manager.RemoveTruck("1")
+	occupancy, _ := manager.Occupancy("y1")
+	if occupancy.Occupied != 0 || len(occupancy.Trucks) != 0 {
+		t.Errorf("Expected an empty yard, got %+v", occupancy)
+	}
+}
+
+func TestYardErrors(t *testing.T) {
+	manager := NewFleetManager()
+	manager.AddTruck("1", 100)
+
+	if err := manager.AddYard(Yard{ID: "y1", Slots: -1}); err == nil {
+		t.Errorf("Expected an error for negative slots")
+	}
+	manager.AddYard(Yard{ID: "y1", Slots: 1})
+	if err := manager.AddYard(Yard{ID: "y1"}); err != ErrYardExist {
+		t.Errorf("Expected yard exist error, got %v", err)
+	}
+	if _, err := manager.CheckIn("y2", "1"); err != ErrYardNotFound {
+		t.Errorf("Expected yard not found error, got %v", err)
+	}
+	if _, err := manager.CheckIn("y1", "2"); err != ErrTruckNotFound {
+		t.Errorf("Expected truck not found error, got %v", err)
+	}
+}